@@ -78,11 +78,11 @@ type download struct {
 
 type images struct {
 	Images
-	o           *miscutils.NewObjParams
-	awsCfg      aws.Config
-	ecrClient   *awsecr.Client
-	region      string
-	httpReqResp httpclient.ReqResp
+	o          *miscutils.NewObjParams
+	awsCfg     aws.Config
+	ecrClient  *awsecr.Client
+	region     string
+	httpClient *httpclient.Client
 }
 
 type Images interface {
@@ -91,7 +91,7 @@ type Images interface {
 	getManifestDigest(imageName, imageTag string) (string, error)
 	getConfigDigest(imageName, imageTag, imageDigest string) (string, error)
 	describeImages(params *awsecr.DescribeImagesInput) (*awsecr.DescribeImagesOutput, error)
-	downloadLayer(downloadURL string) (string, error)
+	downloadLayer(downloadURL string) (*download, error)
 	GetConfigLabels(imageName, imageTag, imageDigest string) (map[string]string, error)
 
 	GetLatestImage(repo, policy string) (string, error)
@@ -110,10 +110,7 @@ func NewImages(objParams *miscutils.NewObjParams, awsConfig aws.Config, httpClie
 		awsCfg: awsConfig,
 	}
 
-	var err error
-	if e.httpReqResp, err = httpclient.NewReqResp(objParams, nil, httpClient, nil); err != nil {
-		e.o.Log.Error("failed to get httpReqResp", "error", err)
-	}
+	e.httpClient = httpclient.NewClient(objParams, nil, httpClient, nil)
 
 	e.ecrClient = e.setEcrClient()
 
@@ -128,7 +125,7 @@ func (e *images) setEcrClient() *awsecr.Client {
 		var err error
 		e.awsCfg, err = aws.NewAwsConfig(e.o, "", e.region)
 		if err != nil {
-			e.o.Log.Log(e.o.Ctx, logging.LevelFatal, "failed to get AWS config", "error", err.Error())
+			logging.LogFatal(e.o.Ctx, e.o.Log, "failed to get AWS config", "error", err.Error())
 		}
 	}
 
@@ -178,10 +175,8 @@ func (e *images) getManifestDigest(imageName, imageTag string) (string, error) {
 		return "", fmt.Errorf("failed to get manifest digest: %s:%s, error: %w", imageName, imageTag, err)
 	}
 
-	if logging.LogLevel <= logging.LevelTrace {
-		for _, image := range output.Images {
-			fmt.Fprintf(e.o.LogOut, "manifest...\n%s\n", *image.ImageManifest)
-		}
+	for _, image := range output.Images {
+		logging.LogDump(e.o.Ctx, e.o.Log, logging.LevelTrace, "manifest", image.ImageManifest)
 	}
 
 	if len(output.Images) == 0 {
@@ -209,10 +204,8 @@ func (e *images) getConfigDigest(imageName, imageTag, imageDigest string) (strin
 		return "", fmt.Errorf("failed to get config digest: %s:%s, error: %w", imageName, imageTag, err)
 	}
 
-	if logging.LogLevel <= logging.LevelTrace {
-		for _, image := range output.Images {
-			fmt.Fprintf(e.o.LogOut, "manifest...\n%s\n", *image.ImageManifest)
-		}
+	for _, image := range output.Images {
+		logging.LogDump(e.o.Ctx, e.o.Log, logging.LevelTrace, "manifest", image.ImageManifest)
 	}
 
 	if len(output.Images) == 0 {
@@ -228,15 +221,16 @@ func (e *images) getConfigDigest(imageName, imageTag, imageDigest string) (strin
 	return m.Config.Digest, nil
 }
 
-func (e *images) downloadLayer(downloadURL string) (string, error) {
+func (e *images) downloadLayer(downloadURL string) (*download, error) {
 	logging.TraceCall()
 	defer logging.TraceExit()
 
-	if err := e.httpReqResp.HTTPreq(&httpclient.Get, &url.URL{Opaque: downloadURL}, nil, nil); err != nil {
-		return "", err
+	d, _, err := httpclient.GetJSON[download](e.o.Ctx, e.httpClient, &url.URL{Opaque: downloadURL})
+	if err != nil {
+		return nil, err
 	}
 
-	return *e.httpReqResp.RespBody(), nil
+	return &d, nil
 }
 
 func (e *images) GetConfigLabels(imageName, imageTag, imageDigest string) (map[string]string, error) {
@@ -256,31 +250,14 @@ func (e *images) GetConfigLabels(imageName, imageTag, imageDigest string) (map[s
 	}
 
 	e.o.Log.Log(e.o.Ctx, slog.LevelDebug, "image layers", "image", imageName, "tag", imageTag)
-	if logging.LogLevel <= logging.LevelTrace {
-		fmt.Fprintf(e.o.LogOut, "download url...\n%s\n", miscutils.IndentJSON(output, 0, 2)) //nolint: mnd
-	}
+	logging.LogDump(e.o.Ctx, e.o.Log, logging.LevelTrace, "download url", output)
 
-	data, err := e.downloadLayer(*output.DownloadUrl)
+	d, err := e.downloadLayer(*output.DownloadUrl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download: %s:%s, error: %w", imageName, imageTag, err)
 	}
 
-	if logging.LogLevel <= logging.LevelTrace {
-		fmt.Fprintf(e.o.LogOut, "download data...\n%s\n", data)
-	}
-
-	d := &download{}
-	if err := json.Unmarshal([]byte(data), d); err != nil {
-		return nil, fmt.Errorf("failed to marshal downloaded data: %s:%s, error: %w", imageName, imageTag, err)
-	}
-
-	if logging.LogLevel <= logging.LevelTrace {
-		fmt.Fprintf(e.o.LogOut, "download loaded...\n%s\n", miscutils.IndentJSON(d, 0, 2)) //nolint: mnd
-	}
-
-	if logging.LogLevel <= logging.LevelTrace {
-		fmt.Fprintf(e.o.LogOut, "download loaded...\n%s\n", d.Config.Labels)
-	}
+	logging.LogDump(e.o.Ctx, e.o.Log, logging.LevelTrace, "download loaded", d)
 
 	return d.Config.Labels, nil
 }
@@ -325,12 +302,7 @@ func (e *images) ApplyPolicy(policy, tag string) bool {
 
 	a, msgs := c.Validate(v)
 	if !a {
-		if logging.LogLevel <= logging.LevelTrace {
-			fmt.Fprintf(e.o.LogOut, "tag: %s failed validation\n", tag)
-			for _, msg := range msgs {
-				fmt.Fprintf(e.o.LogOut, "%s\n", msg)
-			}
-		}
+		logging.LogDump(e.o.Ctx, e.o.Log, logging.LevelTrace, "tag failed validation", map[string]any{"tag": tag, "errors": msgs})
 	}
 	return a
 }
@@ -396,9 +368,7 @@ func (e *images) GetLatestImage(repo, policy string) (string, error) {
 		}
 		for _, image := range output.ImageDetails {
 			for _, i := range image.ImageTags {
-				if logging.LogLevel <= logging.LevelTrace {
-					fmt.Fprintf(e.o.LogOut, "tag: %s\n", i)
-				}
+				e.o.Log.Log(e.o.Ctx, logging.LevelTrace, "image tag", "tag", i)
 				latestImage = e.MaxImage(policy, latestImage, i)
 			}
 		}
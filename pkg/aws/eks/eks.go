@@ -82,7 +82,7 @@ func (e *clusters) setEksClient() *awseks.Client {
 		var err error
 		e.awsCfg, err = aws.NewAwsConfig(e.o, "", e.region)
 		if err != nil {
-			e.o.Log.Log(e.o.Ctx, logging.LevelFatal, "failed to get AWS config", "error", err.Error())
+			logging.LogFatal(e.o.Ctx, e.o.Log, "failed to get AWS config", "error", err.Error())
 		}
 	}
 	return awseks.NewFromConfig(e.awsCfg.NewConfig("", e.region))
@@ -167,9 +167,7 @@ func (e *clusters) GetClustersByTags(tags map[string]string) ([]*awsekstypes.Clu
 		if err != nil {
 			return nil, fmt.Errorf("failed to describe cluster: %s, error: %w", cluster, err)
 		}
-		if logging.LogLevel <= logging.LevelTrace {
-			fmt.Fprintf(e.o.LogOut, "cluster info...\n%s\n", miscutils.IndentJSON(clusterInfo, 0, 2)) //nolint: mnd
-		}
+		logging.LogDump(e.o.Ctx, e.o.Log, logging.LevelTrace, "cluster info", clusterInfo)
 
 		if e.matchTags(clusterInfo.Cluster.Tags, tags) {
 			matchingClusters = append(matchingClusters, clusterInfo.Cluster)
@@ -2,8 +2,10 @@ package aws
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
@@ -28,6 +30,8 @@ type s3Service struct {
 	middlewareFunc string
 	downloader     *manager.Downloader
 	uploader       *manager.Uploader
+	callerARN      string
+	callerOnce     sync.Once
 }
 
 type S3service interface {
@@ -63,7 +67,7 @@ func (s *s3Service) InitClient() *s3.Client {
 		var err error
 		s.awsCfg, err = aws.NewAwsConfig(s.o, s.profile, s.region)
 		if err != nil {
-			s.o.Log.Log(s.o.Ctx, logging.LevelFatal, "failed to get AWS config", "error", err.Error())
+			logging.LogFatal(s.o.Ctx, s.o.Log, "failed to get AWS config", "error", err.Error())
 		}
 	}
 	s.s3Client = s3.NewFromConfig(s.awsCfg.NewConfig(s.profile, s.region))
@@ -88,10 +92,19 @@ func NewS3service(objParams *miscutils.NewObjParams, awsConfig aws.Config, profi
 	return &s
 }
 
-func (s *s3Service) UploadDataToS3(bucket, key, data string) error {
+func (s *s3Service) UploadDataToS3(bucket, key, data string) (err error) {
+	defer func() {
+		logging.Audit(s.o.Ctx, logging.AuditEvent{
+			Actor:  s.actor(),
+			Action: "UploadDataToS3",
+			Target: fmt.Sprintf("s3://%s/%s", bucket, key),
+			Params: map[string]any{"size": len(data)},
+		}, err)
+	}()
+
 	ctx, cancel := context.WithTimeout(s.o.Ctx, time.Second*60) //nolint: mnd
 	defer cancel()
-	_, err := s.uploader.Upload(ctx, &s3.PutObjectInput{
+	_, err = s.uploader.Upload(ctx, &s3.PutObjectInput{
 		Bucket: awssdk.String(bucket),
 		Key:    awssdk.String(key),
 		Body:   strings.NewReader(data),
@@ -99,6 +112,22 @@ func (s *s3Service) UploadDataToS3(bucket, key, data string) error {
 	return err
 }
 
+// actor returns the ARN of the AWS identity used for requests, it is looked up once when an audit log is set.
+func (s *s3Service) actor() string {
+	if logging.GetAuditLog() == nil {
+		return ""
+	}
+	s.callerOnce.Do(func() {
+		arn, err := aws.NewSTSService(s.awsCfg.NewConfig(s.profile, s.region)).GetCallerARN(s.o.Ctx)
+		if err != nil {
+			s.o.Log.Error("failed to get AWS caller identity", "error", err.Error())
+			return
+		}
+		s.callerARN = *arn
+	})
+	return s.callerARN
+}
+
 func (s *s3Service) DownloadFileFromS3(bucket, key, outfile string) error {
 	file, err := os.Create(outfile)
 	miscutils.CheckError(err)
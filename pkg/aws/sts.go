@@ -30,3 +30,12 @@ func (s *STSService) GetAccountID() (*string, error) {
 	}
 	return req.Account, nil
 }
+
+func (s *STSService) GetCallerARN(ctx context.Context) (*string, error) {
+	input := &sts.GetCallerIdentityInput{}
+	req, err := s.Client.GetCallerIdentity(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return req.Arn, nil
+}
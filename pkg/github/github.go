@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	githubapi "github.com/google/go-github/v66/github"
@@ -23,6 +24,8 @@ type apiClient struct {
 	dryRun       bool
 	gitHubClient *githubapi.Client
 	org          string
+	login        string
+	loginOnce    sync.Once
 }
 
 type API interface {
@@ -87,9 +90,12 @@ func (g *apiClient) GetRepoVariable(repoName, varName string) (string, error) {
 	return variableInfo.Value, nil
 }
 
-func (g *apiClient) SetRepoVariable(repo, varName, varValue string) error {
+func (g *apiClient) SetRepoVariable(repo, varName, varValue string) (err error) {
 	logging.TraceCall()
 	defer logging.TraceExit()
+	defer func() {
+		g.audit("SetRepoVariable", repo, g.dryRun, map[string]any{"variable": varName, "value": varValue}, err)
+	}()
 
 	varInfo := &githubapi.ActionsVariable{
 		Name:  varName,
@@ -99,7 +105,7 @@ func (g *apiClient) SetRepoVariable(repo, varName, varValue string) error {
 		g.o.Log.Info("dry run, skipping update of repository variable", "repository", repo, "variable", varName, "value", varValue)
 		return nil
 	}
-	_, err := g.gitHubClient.Actions.UpdateRepoVariable(g.o.Ctx, g.org, repo, varInfo)
+	_, err = g.gitHubClient.Actions.UpdateRepoVariable(g.o.Ctx, g.org, repo, varInfo)
 	if err != nil {
 		return fmt.Errorf("failed to update repo: %s variable: %s, error: %w", repo, varName, err)
 	}
@@ -126,9 +132,7 @@ func (g *apiClient) GetWorkflowJob(wfName, wfTitle, repo, branch, event string)
 		}
 
 		var workflowID int64
-		if logging.LogLevel <= logging.LevelTrace {
-			fmt.Fprintf(g.o.LogOut, "workflows...\n%s\n", miscutils.IndentJSON(runs.WorkflowRuns, 0, 2))
-		}
+		logging.LogDump(g.o.Ctx, g.o.Log, logging.LevelTrace, "workflows", runs.WorkflowRuns)
 		for _, run := range runs.WorkflowRuns {
 			if *(run.Path) == wfName &&
 				*(run.DisplayTitle) == wfTitle {
@@ -141,27 +145,28 @@ func (g *apiClient) GetWorkflowJob(wfName, wfTitle, repo, branch, event string)
 		}
 		nextPage = response.NextPage
 	}
-	return 0, "", fmt.Errorf("failed to find workflow") //nolint: err113
+	return 0, "", logging.NewError("failed to find workflow", "repo", repo, "workflow", wfName, "title", wfTitle)
 }
 
-func (g *apiClient) SubmitWorkflow(repo, branch, wfName string, inputs map[string]interface{}) error {
+func (g *apiClient) SubmitWorkflow(repo, branch, wfName string, inputs map[string]interface{}) (err error) {
 	logging.TraceCall()
 	defer logging.TraceExit()
+	defer func() {
+		g.audit("SubmitWorkflow", repo, false, map[string]any{"branch": branch, "workflow": wfName, "inputs": inputs}, err)
+	}()
 
 	event := githubapi.CreateWorkflowDispatchEventRequest{
 		Ref:    branch,
 		Inputs: inputs,
 	}
 
-	if logging.LogLevel <= logging.LevelTrace {
-		fmt.Fprintf(g.o.LogOut, "input...\n%s\n", miscutils.IndentJSON(event.Inputs, 0, 2))
-	}
+	logging.LogDump(g.o.Ctx, g.o.Log, logging.LevelTrace, "input", event.Inputs)
 	response, err := g.gitHubClient.Actions.CreateWorkflowDispatchEventByFileName(g.o.Ctx, g.org, repo, wfName, event)
 	if err != nil {
 		return fmt.Errorf("failed to trigger workflow: %s, error: %w", wfName, err)
 	}
 	if response.StatusCode != 204 {
-		return fmt.Errorf("workflow dispatch response code: %d", response.StatusCode) //nolint: err113
+		return logging.NewError("unexpected workflow dispatch response code", "repo", repo, "workflow", wfName, "status", response.StatusCode)
 	}
 	return nil
 }
@@ -174,8 +179,33 @@ func (g *apiClient) GetWorkflowRunByID(repo string, id int64) (*githubapi.Workfl
 	if err != nil {
 		return nil, err
 	}
-	if logging.LogLevel <= logging.LevelTrace {
-		fmt.Fprintf(g.o.LogOut, "workflow...\n%s\n", miscutils.IndentJSON(workflow, 0, 2))
-	}
+	logging.LogDump(g.o.Ctx, g.o.Log, logging.LevelTrace, "workflow", workflow)
 	return workflow, nil
 }
+
+// audit records a mutating operation on a repository in the audit log.
+func (g *apiClient) audit(action, repo string, dryRun bool, params map[string]any, err error) {
+	if logging.GetAuditLog() == nil {
+		return
+	}
+	logging.Audit(g.o.Ctx, logging.AuditEvent{
+		Actor:  g.tokenOwner(),
+		Action: action,
+		Target: fmt.Sprintf("%s/%s", g.org, repo),
+		Params: params,
+		DryRun: dryRun,
+	}, err)
+}
+
+// tokenOwner returns the login of the user the GitHub token belongs to, it is looked up once.
+func (g *apiClient) tokenOwner() string {
+	g.loginOnce.Do(func() {
+		user, _, err := g.gitHubClient.Users.Get(g.o.Ctx, "")
+		if err != nil {
+			g.o.Log.Error("failed to get GitHub token owner", "error", err.Error())
+			return
+		}
+		g.login = user.GetLogin()
+	})
+	return g.login
+}
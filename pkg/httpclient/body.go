@@ -0,0 +1,185 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/paul-carlton/goutils/pkg/logging"
+)
+
+const (
+	contentTypeJSON   = "application/json"
+	contentTypeForm   = "application/x-www-form-urlencoded"
+	contentTypeBinary = "application/octet-stream"
+)
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"") //nolint: gochecknoglobals
+
+// FormFile is a file uploaded in a Multipart request body.
+type FormFile struct {
+	Field       string    // The form field name.
+	FileName    string    // The file name sent to the server, defaults to the base name of Path.
+	ContentType string    // The content type of the file, defaults to application/octet-stream.
+	Path        string    // The file to upload, opened for each attempt so the request can be retried.
+	Content     io.Reader // The content to upload if Path is not set, a request with Content is not retried.
+}
+
+// Multipart is a multipart/form-data request body. It is streamed to the server as it is written rather than being
+// held in memory.
+type Multipart struct {
+	Fields url.Values // Form fields sent before the files.
+	Files  []FormFile // Files to upload.
+}
+
+// requestBody is an encoded request body that can be opened for each attempt to send it.
+type requestBody struct {
+	contentType string
+	data        []byte                        // The body if it is held in memory.
+	open        func() (io.ReadCloser, error) // Opens a streamed body if data is nil.
+	replayable  bool                          // Whether the body can be sent more than once.
+}
+
+// reader returns a reader for an attempt to send the body, or nil if there is no body.
+func (b *requestBody) reader() (io.Reader, error) {
+	switch {
+	case b == nil:
+		return nil, nil
+	case b.open != nil:
+		return b.open()
+	default:
+		return bytes.NewReader(b.data), nil
+	}
+}
+
+// encodeBody returns the request body. A string is sent as is and assumed to be JSON, []byte and io.Reader values are
+// sent as is with the content type specified, url.Values are form encoded, a Multipart is streamed as multipart form
+// data and other values are marshalled to JSON.
+func (c *Client) encodeBody(ctx context.Context, body interface{}, contentType string) (*requestBody, error) {
+	var b *requestBody
+	switch v := body.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		c.o.Log.Log(ctx, logging.LevelTrace, "body is a string, assuming it is valid json")
+		b = &requestBody{contentType: contentTypeJSON, data: []byte(v), replayable: true}
+	case []byte:
+		b = &requestBody{contentType: contentTypeBinary, data: v, replayable: true}
+	case url.Values:
+		b = &requestBody{contentType: contentTypeForm, data: []byte(v.Encode()), replayable: true}
+	case *Multipart:
+		if v == nil {
+			return nil, requestBodyError("nil multipart body")
+		}
+		b = v.body()
+	case Multipart:
+		b = v.body()
+	case io.Reader:
+		c.o.Log.Log(ctx, logging.LevelTrace, "streaming body")
+		b = &requestBody{contentType: contentTypeBinary, open: func() (io.ReadCloser, error) { return io.NopCloser(v), nil }}
+	default:
+		c.o.Log.Log(ctx, logging.LevelTrace, "body is not a string, marshalling to json")
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, requestBodyError(err.Error())
+		}
+		b = &requestBody{contentType: contentTypeJSON, data: data, replayable: true}
+	}
+
+	if len(contentType) > 0 {
+		b.contentType = contentType
+	}
+	if b.data != nil {
+		logging.LogDump(ctx, c.o.Log, logging.LevelTrace, "body", b.data)
+	}
+	return b, nil
+}
+
+// body returns a request body that writes the form to a pipe as it is read.
+func (m *Multipart) body() *requestBody {
+	boundary := multipart.NewWriter(nil).Boundary()
+	replayable := true
+	for _, file := range m.Files {
+		if len(file.Path) == 0 {
+			replayable = false
+		}
+	}
+
+	open := func() (io.ReadCloser, error) {
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(m.write(pw, boundary))
+		}()
+		return pr, nil
+	}
+	return &requestBody{contentType: "multipart/form-data; boundary=" + boundary, open: open, replayable: replayable}
+}
+
+// write writes the form to the writer using the boundary specified.
+func (m *Multipart) write(w io.Writer, boundary string) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return requestBodyError(err.Error())
+	}
+	for name, values := range m.Fields {
+		for _, value := range values {
+			if err := mw.WriteField(name, value); err != nil {
+				return requestBodyError(err.Error())
+			}
+		}
+	}
+	for _, file := range m.Files {
+		if err := file.write(mw); err != nil {
+			return err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return requestBodyError(err.Error())
+	}
+	return nil
+}
+
+// write writes the file to a multipart form.
+func (f *FormFile) write(mw *multipart.Writer) error {
+	content := f.Content
+	fileName := f.FileName
+	if len(f.Path) > 0 {
+		file, err := os.Open(f.Path)
+		if err != nil {
+			return requestBodyError(err.Error())
+		}
+		defer file.Close()
+		content = file
+		if len(fileName) == 0 {
+			fileName = filepath.Base(f.Path)
+		}
+	}
+	if content == nil {
+		return requestBodyError("no content for file: " + f.Field)
+	}
+	contentType := f.ContentType
+	if len(contentType) == 0 {
+		contentType = contentTypeBinary
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition",
+		fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(f.Field), quoteEscaper.Replace(fileName)))
+	header.Set("Content-Type", contentType)
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return requestBodyError(err.Error())
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return requestBodyError(err.Error())
+	}
+	return nil
+}
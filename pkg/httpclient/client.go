@@ -0,0 +1,236 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/paul-carlton/goutils/pkg/logging"
+	"github.com/paul-carlton/goutils/pkg/miscutils"
+)
+
+// Client sends HTTP requests, it holds no per request state and is safe for concurrent use.
+type Client struct {
+	o       *miscutils.NewObjParams
+	client  *http.Client
+	options options
+}
+
+// Request describes an HTTP request to be sent by a Client.
+type Request struct {
+	Method         string      // The HTTP method, defaults to GET.
+	URL            *url.URL    // The URL to send the request to.
+	Header         Header      // Header fields to set on the request.
+	Body           interface{} // The request body, see Client.Do for the types supported.
+	ContentType    string      // The content type of the body, defaults to a type chosen for the body.
+	AcceptedStatus []int       // The status codes that indicate success, overriding those set using WithAcceptedStatus.
+}
+
+// Response holds the result of a request. It is created for each call to Do and is not modified afterwards.
+type Response struct {
+	StatusCode int           // The status code of the final attempt.
+	Status     string        // The status line of the final attempt, e.g. "200 OK".
+	Header     http.Header   // The response header fields of the final attempt.
+	Body       []byte        // The response body of the final attempt, see GetJSON for responses that are decoded.
+	Duration   time.Duration // The time taken for all attempts, including waiting between them.
+	Attempts   int           // The number of attempts made.
+
+	decodeErr error // The error returned when decoding the response body.
+}
+
+// Text returns the response body as a string.
+func (r *Response) Text() string {
+	return string(r.Body)
+}
+
+// NewClient returns a Client that sends requests with the timeout specified, retrying failed requests according to
+// the retry policy, which defaults to DefaultRetryPolicy. Requests are sent using a copy of the client, if specified,
+// keeping its timeout unless one is specified, with its transport replaced by the transport specified, or the client's own transport, wrapped in the middleware
+// added using Use. If neither a client nor a transport is specified a shared transport that pools connections and
+// honours proxy environmental variables is used.
+func NewClient(objParams *miscutils.NewObjParams, timeout *time.Duration, client *http.Client, transport http.RoundTripper, opts ...Option) *Client {
+	logging.TraceCall()
+	defer logging.TraceExit()
+
+	if objParams.Ctx == nil {
+		objParams.Ctx = context.Background()
+	}
+
+	if objParams.Log == nil {
+		objParams.Log = logging.NewTextLoggerTo(objParams.LogOut)
+	}
+
+	options := newOptions(opts)
+	return &Client{o: objParams, client: newHTTPClient(client, transport, timeout, options.middleware), options: options}
+}
+
+// newHTTPClient returns a copy of the client, or a new client, whose transport is wrapped in the middleware.
+func newHTTPClient(client *http.Client, transport http.RoundTripper, timeout *time.Duration, middleware []Middleware) *http.Client {
+	httpClient := &http.Client{Timeout: DefaultTimeout}
+	if client != nil {
+		*httpClient = *client
+	}
+	if timeout != nil {
+		httpClient.Timeout = *timeout
+	}
+	if transport == nil {
+		transport = httpClient.Transport
+	}
+	if transport == nil {
+		transport = tr
+	}
+	httpClient.Transport = Chain(transport, middleware...)
+	return httpClient
+}
+
+// Do sends a request, retrying it according to the retry policy. The response is returned if one was received, with an
+// error if its status code is not one of the accepted status codes.
+//
+// The request body may be a string, which is sent as JSON, a []byte or io.Reader, which are sent as is with the
+// content type application/octet-stream unless ContentType is set, url.Values, which are form encoded, a Multipart form
+// or any other value, which is marshalled to JSON. An io.Reader is streamed to the server without being read into
+// memory so it is sent once and not retried, as is a Multipart form that includes a FormFile with Content.
+func (c *Client) Do(ctx context.Context, req Request) (*Response, error) {
+	logging.TraceCall()
+	defer logging.TraceExit()
+
+	return c.do(ctx, req, nil)
+}
+
+// do sends a request, retrying it according to the retry policy. If decode is specified it is called to read the body
+// of a response with an accepted status code instead of the body being held in the Response.
+func (c *Client) do(ctx context.Context, req Request, decode func(io.Reader) error) (*Response, error) {
+	if ctx == nil {
+		ctx = c.o.Ctx
+	}
+	if len(req.Method) == 0 {
+		req.Method = Get
+	}
+	if req.URL == nil {
+		return nil, fmt.Errorf("%w: no url specified", ErrorInvalidURL)
+	}
+
+	body, err := c.encodeBody(ctx, req.Body, req.ContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	target := logging.GetRedactor().RedactString(req.URL.String())
+	c.o.Log.DebugContext(ctx, "sending to", "method", req.Method, "url", target)
+
+	policy := c.options.retry
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		httpReq, err := newRequest(ctx, req, body)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.send(req, httpReq, decode)
+		var retry bool
+		if err != nil {
+			c.o.Log.WarnContext(ctx, "failed to send request", slog.String("error", err.Error()))
+			retry = policy.retryableError(ctx, req.Method, err)
+		} else {
+			resp.Attempts, resp.Duration = attempt, time.Since(start)
+			retry = policy.retryableStatus(req.Method, resp.StatusCode)
+		}
+
+		if retry && attempt < policy.MaxAttempts && (body == nil || body.replayable) {
+			delay := policy.backoff(attempt, resp.header())
+			if policy.MaxElapsed == 0 || time.Since(start)+delay < policy.MaxElapsed {
+				c.o.Log.WarnContext(ctx, "retrying request", "url", target, "attempt", attempt, "delay", delay.String())
+				if err := wait(ctx, delay); err != nil {
+					return nil, fmt.Errorf("%w: %w", ErrorRequestFailed, err)
+				}
+				continue
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.decodeErr != nil {
+			return resp, resp.decodeErr
+		}
+
+		if c.accepted(req, resp.StatusCode) {
+			return resp, nil
+		}
+
+		return resp, requestError(fmt.Sprintf("failed: %s %s", resp.Status, resp.Text()))
+	}
+}
+
+// accepted reports whether a status code indicates the request succeeded.
+func (c *Client) accepted(req Request, code int) bool {
+	if len(req.AcceptedStatus) > 0 {
+		return slices.Contains(req.AcceptedStatus, code)
+	}
+	return c.options.accepted(code)
+}
+
+// send sends a request and reads the response, passing the body of a response with an accepted status code to decode
+// if it is specified. An error returned by decode is held in the response so it is not treated as a failure to send.
+func (c *Client) send(req Request, httpReq *http.Request, decode func(io.Reader) error) (*Response, error) {
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	response := &Response{StatusCode: resp.StatusCode, Status: resp.Status, Header: resp.Header}
+	if decode != nil && c.accepted(req, resp.StatusCode) {
+		snippet := &snippetWriter{}
+		if err := decode(io.TeeReader(resp.Body, snippet)); err != nil {
+			response.Body, response.decodeErr = snippet.data, err
+			return response, nil
+		}
+		_, _ = io.Copy(io.Discard, resp.Body) //nolint: errcheck // drain the body so the connection can be reused.
+		return response, nil
+	}
+
+	if response.Body, err = io.ReadAll(resp.Body); err != nil {
+		return nil, readingResponseBodyError(err.Error())
+	}
+	return response, nil
+}
+
+// header returns the response header fields, or nil if there is no response.
+func (r *Response) header() http.Header {
+	if r == nil {
+		return nil
+	}
+	return r.Header
+}
+
+// newRequest returns a request for an attempt to send the body.
+func newRequest(ctx context.Context, req Request, body *requestBody) (*http.Request, error) {
+	reader, err := body.reader()
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL.String(), reader)
+	if err != nil {
+		if closer, ok := reader.(io.Closer); ok {
+			_ = closer.Close() //nolint: errcheck
+		}
+		return nil, requestError(err.Error())
+	}
+
+	for k, v := range req.Header {
+		if len(v) > 0 {
+			httpReq.Header.Set(k, v)
+		}
+	}
+	if body != nil && len(httpReq.Header.Get("Content-Type")) == 0 {
+		httpReq.Header.Set("Content-Type", body.contentType)
+	}
+	return httpReq, nil
+}
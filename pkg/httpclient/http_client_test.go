@@ -0,0 +1,440 @@
+package httpclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/paul-carlton/goutils/pkg/httpclient"
+	"github.com/paul-carlton/goutils/pkg/logging"
+	"github.com/paul-carlton/goutils/pkg/miscutils"
+)
+
+func testObjParams() *miscutils.NewObjParams {
+	return &miscutils.NewObjParams{Ctx: context.Background(), Log: logging.NewLoggerTo(io.Discard), LogOut: io.Discard}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy := httpclient.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxAttempts = 3
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		testNum  int
+		method   string
+		statuses []int
+		header   string
+		url      string
+		attempts int32
+		success  bool
+	}{
+		{1, httpclient.Get, []int{http.StatusTooManyRequests, http.StatusOK}, "0", "", 2, true},
+		{2, httpclient.Get, []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusGatewayTimeout, http.StatusOK}, "", "", 3, false},
+		{3, httpclient.Get, []int{http.StatusNotFound, http.StatusOK}, "", "", 1, false},
+		{4, httpclient.Get, nil, "", closed.URL, 0, false},
+		{5, httpclient.Post, []int{http.StatusBadGateway, http.StatusOK}, "", "", 1, false},
+		{6, httpclient.Post, []int{http.StatusGatewayTimeout, http.StatusOK}, "", "", 1, false},
+		{7, httpclient.Post, []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}, "0", "", 3, true},
+	}
+
+	for _, test := range tests {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			n := attempts.Add(1)
+			if len(test.header) > 0 {
+				w.Header().Set("Retry-After", test.header)
+			}
+			w.WriteHeader(test.statuses[n-1])
+		}))
+		target := server.URL
+		if len(test.url) > 0 {
+			target = test.url
+		}
+		u, _ := url.Parse(target) //nolint: errcheck
+
+		reqResp, _ := httpclient.NewReqResp(testObjParams(), nil, nil, nil, httpclient.WithRetryPolicy(policy)) //nolint: errcheck
+		err := reqResp.HTTPreq(&test.method, u, nil, nil)
+		server.Close()
+
+		if (err == nil) != test.success || attempts.Load() != test.attempts {
+			t.Errorf("\nTest: %d\nExpected: success: %t, attempts: %d\nGot.....: error: %v, attempts: %d",
+				test.testNum, test.success, test.attempts, err, attempts.Load())
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	order := []string{}
+	record := func(name string) httpclient.Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+":"+req.Header.Get("Authorization"))
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	transport := httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		order = append(order, "transport")
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok")), Request: req}, nil
+	})
+
+	tests := []struct {
+		testNum   int
+		client    *http.Client
+		transport http.RoundTripper
+		expected  []string
+	}{
+		{1, nil, transport, []string{"outer:", "inner:Bearer x", "transport"}},
+		{2, &http.Client{Transport: transport}, nil, []string{"outer:", "inner:Bearer x", "transport"}},
+	}
+
+	for _, test := range tests {
+		order = []string{}
+		reqResp, _ := httpclient.NewReqResp(testObjParams(), nil, test.client, test.transport, //nolint: errcheck
+			httpclient.Use(record("outer"), httpclient.WithHeader("Authorization", "Bearer x"), record("inner")))
+		err := reqResp.HTTPreq(&httpclient.Get, &url.URL{Scheme: "https", Host: "example.invalid"}, nil, nil)
+		if err != nil || !reflect.DeepEqual(order, test.expected) || *reqResp.RespBody() != "ok" {
+			t.Errorf("\nTest: %d\nExpected: %v\nGot.....: %v, error: %v", test.testNum, test.expected, order, err)
+		}
+	}
+}
+
+func TestClientDo(t *testing.T) {
+	var attempts sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := attempts.LoadOrStore(r.URL.Path, new(atomic.Int32))
+		if n.(*atomic.Int32).Add(1) == 1 && strings.HasSuffix(r.URL.Path, "/retry") { //nolint: forcetypeassert
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("X-Path", r.URL.Path)
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	client := httpclient.NewClient(testObjParams(), nil, nil, nil)
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for testNum := 1; testNum <= workers; testNum++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			path := "/" + strconv.Itoa(testNum)
+			expectedAttempts := 1
+			if testNum%2 == 0 {
+				path += "/retry"
+				expectedAttempts = 2
+			}
+			u, _ := url.Parse(server.URL + path) //nolint: errcheck
+			resp, err := client.Do(context.Background(), httpclient.Request{URL: u})
+			if err != nil || resp.StatusCode != http.StatusOK || resp.Text() != path ||
+				resp.Header.Get("X-Path") != path || resp.Attempts != expectedAttempts {
+				t.Errorf("\nTest: %d\nExpected: status: %d, body: %s, attempts: %d\nGot.....: %+v, error: %v",
+					testNum, http.StatusOK, path, expectedAttempts, resp, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRequestBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType := r.Header.Get("Content-Type")
+		if strings.HasPrefix(contentType, "multipart/form-data") {
+			if err := r.ParseMultipartForm(1024); err != nil { //nolint: mnd
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			file, header, _ := r.FormFile("file") //nolint: errcheck
+			data, _ := io.ReadAll(file)           //nolint: errcheck
+			fmt.Fprintf(w, "%s multipart %s %s:%s", r.Method, r.FormValue("name"), header.Filename, data)
+			return
+		}
+		data, _ := io.ReadAll(r.Body) //nolint: errcheck
+		fmt.Fprintf(w, "%s %s %s", r.Method, contentType, data)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL) //nolint: errcheck
+
+	path := filepath.Join(t.TempDir(), "upload.txt")
+	if err := os.WriteFile(path, []byte("from file"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		testNum     int
+		method      string
+		body        interface{}
+		contentType string
+		expected    string
+	}{
+		{1, httpclient.Put, map[string]int{"replicas": 2}, "", `PUT application/json {"replicas":2}`},
+		{2, httpclient.Patch, `{"op":"add"}`, "application/merge-patch+json", `PATCH application/merge-patch+json {"op":"add"}`},
+		{3, httpclient.Delete, []byte("raw"), "text/plain", "DELETE text/plain raw"},
+		{4, httpclient.Post, strings.NewReader("streamed"), "", "POST application/octet-stream streamed"},
+		{5, httpclient.Post, url.Values{"a": {"1"}, "b": {"x y"}}, "", "POST application/x-www-form-urlencoded a=1&b=x+y"},
+		{6, httpclient.Post, &httpclient.Multipart{Fields: url.Values{"name": {"test"}},
+			Files: []httpclient.FormFile{{Field: "file", Path: path}}}, "", "POST multipart test upload.txt:from file"},
+		{7, httpclient.Put, httpclient.Multipart{Files: []httpclient.FormFile{
+			{Field: "file", FileName: "data.bin", Content: strings.NewReader("from reader")}}}, "", "PUT multipart  data.bin:from reader"},
+	}
+
+	client := httpclient.NewClient(testObjParams(), nil, nil, nil)
+	for _, test := range tests {
+		resp, err := client.Do(context.Background(), httpclient.Request{Method: test.method, URL: u, Body: test.body, ContentType: test.contentType})
+		if err != nil || resp.Text() != test.expected {
+			t.Errorf("\nTest: %d\nExpected: %s\nGot.....: %+v, error: %v", test.testNum, test.expected, resp, err)
+		}
+	}
+
+	var multipart *httpclient.Multipart
+	if _, err := client.Do(context.Background(), httpclient.Request{Method: httpclient.Post, URL: u, Body: multipart}); !errors.Is(err, httpclient.ErrorRequestBodyInvalid) {
+		t.Errorf("\nExpected: error: %v\nGot.....: %v", httpclient.ErrorRequestBodyInvalid, err)
+	}
+}
+
+func TestAcceptedStatus(t *testing.T) {
+	tests := []struct {
+		testNum int
+		status  int
+		option  []int
+		request []int
+		success bool
+	}{
+		{1, http.StatusAccepted, nil, nil, true},
+		{2, http.StatusNotModified, nil, nil, false},
+		{3, http.StatusCreated, []int{http.StatusOK}, nil, false},
+		{4, http.StatusNotFound, []int{http.StatusOK}, []int{http.StatusOK, http.StatusNotFound}, true},
+	}
+
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(test.status)
+		}))
+		u, _ := url.Parse(server.URL) //nolint: errcheck
+
+		client := httpclient.NewClient(testObjParams(), nil, nil, nil, httpclient.WithAcceptedStatus(test.option...))
+		resp, err := client.Do(context.Background(), httpclient.Request{URL: u, AcceptedStatus: test.request})
+		server.Close()
+
+		if (err == nil) != test.success || resp == nil || resp.StatusCode != test.status {
+			t.Errorf("\nTest: %d\nExpected: success: %t, status: %d\nGot.....: %+v, error: %v",
+				test.testNum, test.success, test.status, resp, err)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type item struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	tests := []struct {
+		testNum   int
+		status    int
+		body      string
+		strict    bool
+		expected  item
+		requestID string
+		err       error
+	}{
+		{1, http.StatusOK, `{"name":"a","count":2}`, false, item{"a", 2}, "", nil},
+		{2, http.StatusOK, `{"name":"a","count":2,"extra":true}`, false, item{"a", 2}, "", nil},
+		{3, http.StatusOK, `{"name":"a","count":2,"extra":true}`, true, item{}, "req-3", httpclient.ErrorDecodingRespBody},
+		{4, http.StatusOK, `{"name":`, false, item{}, "req-4", httpclient.ErrorDecodingRespBody},
+		{5, http.StatusNotFound, `{"message":"not found"}`, false, item{}, "req-5", httpclient.ErrorRequestFailed},
+		{6, http.StatusNoContent, "", false, item{}, "", nil},
+	}
+
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Accept") != "application/json" {
+				w.WriteHeader(http.StatusNotAcceptable)
+				return
+			}
+			if r.Method == http.MethodPost {
+				in := item{}
+				if err := json.NewDecoder(r.Body).Decode(&in); err != nil || in.Name != "in" ||
+					r.Header.Get("Content-Type") != "application/json" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+			}
+			w.Header().Set("X-Request-Id", fmt.Sprintf("req-%d", test.testNum))
+			w.WriteHeader(test.status)
+			_, _ = w.Write([]byte(test.body))
+		}))
+		u, _ := url.Parse(server.URL) //nolint: errcheck
+		client := httpclient.NewClient(testObjParams(), nil, nil, nil)
+
+		opts := []httpclient.JSONOption{}
+		if test.strict {
+			opts = append(opts, httpclient.Strict())
+		}
+		got, _, getErr := httpclient.GetJSON[item](context.Background(), client, u, opts...)
+		posted, _, postErr := httpclient.PostJSON[item, item](context.Background(), client, u, item{Name: "in"}, opts...)
+		server.Close()
+
+		for _, result := range []struct {
+			got item
+			err error
+		}{{got, getErr}, {posted, postErr}} {
+			httpErr := &httpclient.HTTPError{}
+			if result.got != test.expected || !errors.Is(result.err, test.err) ||
+				(test.err != nil && (!errors.As(result.err, &httpErr) || httpErr.StatusCode != test.status ||
+					httpErr.RequestID != test.requestID || httpErr.Body != test.body)) {
+				t.Errorf("\nTest: %d\nExpected: %+v, error: %v\nGot.....: %+v, error: %v",
+					test.testNum, test.expected, test.err, result.got, result.err)
+			}
+		}
+	}
+}
+
+func TestPostJSONBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body) //nolint: errcheck
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL) //nolint: errcheck
+	client := httpclient.NewClient(testObjParams(), nil, nil, nil)
+
+	tests := []struct {
+		testNum  int
+		post     func() (string, error)
+		expected string
+	}{
+		{1, func() (string, error) {
+			out, _, err := httpclient.PostJSON[string, string](context.Background(), client, u, "not json")
+			return out, err
+		}, "not json"},
+		{2, func() (string, error) {
+			out, _, err := httpclient.PostJSON[[]byte, string](context.Background(), client, u, []byte("raw"))
+			return out, err
+		}, "cmF3"},
+	}
+
+	for _, test := range tests {
+		got, err := test.post()
+		if err != nil || got != test.expected {
+			t.Errorf("\nTest: %d\nExpected: %s\nGot.....: %s, error: %v", test.testNum, test.expected, got, err)
+		}
+	}
+}
+
+func TestRetryErrors(t *testing.T) {
+	policy := httpclient.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxAttempts = 3
+
+	dropped := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack() //nolint: forcetypeassert
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer dropped.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		testNum  int
+		method   string
+		url      string
+		attempts int32
+	}{
+		{1, httpclient.Get, dropped.URL, 3},
+		{2, httpclient.Post, dropped.URL, 1},
+		{3, httpclient.Patch, dropped.URL, 1},
+		{4, httpclient.Post, closed.URL, 3},
+	}
+
+	for _, test := range tests {
+		var attempts atomic.Int32
+		count := func(next http.RoundTripper) http.RoundTripper {
+			return httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				attempts.Add(1)
+				return next.RoundTrip(req)
+			})
+		}
+		u, _ := url.Parse(test.url) //nolint: errcheck
+		client := httpclient.NewClient(testObjParams(), nil, nil, nil, httpclient.WithRetryPolicy(policy), httpclient.Use(count))
+		_, err := client.Do(context.Background(), httpclient.Request{Method: test.method, URL: u, Body: "{}"})
+		if err == nil || attempts.Load() != test.attempts {
+			t.Errorf("\nTest: %d\nExpected: attempts: %d\nGot.....: attempts: %d, error: %v",
+				test.testNum, test.attempts, attempts.Load(), err)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL) //nolint: errcheck
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := httpclient.NewClient(testObjParams(), nil, nil, nil).Do(ctx, httpclient.Request{URL: u})
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, httpclient.ErrorRequestFailed) {
+		t.Errorf("\nExpected: error wrapping: %v\nGot.....: %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	saved := httpclient.DefaultTimeout
+	httpclient.DefaultTimeout = 50 * time.Millisecond
+	defer func() { httpclient.DefaultTimeout = saved }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL) //nolint: errcheck
+
+	short := 50 * time.Millisecond
+
+	tests := []struct {
+		testNum  int
+		timeout  *time.Duration
+		client   *http.Client
+		timedOut bool
+	}{
+		{1, nil, nil, true},
+		{2, nil, &http.Client{}, false},
+		{3, &short, &http.Client{}, true},
+		{4, nil, &http.Client{Timeout: time.Second}, false},
+	}
+
+	for _, test := range tests {
+		client := httpclient.NewClient(testObjParams(), test.timeout, test.client, nil, httpclient.WithRetryPolicy(httpclient.NoRetry()))
+		_, err := client.Do(context.Background(), httpclient.Request{URL: u})
+		if (err != nil) != test.timedOut {
+			t.Errorf("\nTest: %d\nExpected: timed out: %t\nGot.....: %v", test.testNum, test.timedOut, err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/paul-carlton/goutils/pkg/logging"
+)
+
+// snippetSize is the maximum number of bytes of a response body held in an HTTPError.
+const snippetSize = 1024
+
+// RequestIDHeaders are the response header fields checked, in order, for the request ID reported in an HTTPError.
+var RequestIDHeaders = []string{ //nolint: gochecknoglobals
+	"X-Request-Id", "X-Amz-Request-Id", "X-Amzn-RequestId", "X-GitHub-Request-Id", "X-Correlation-Id",
+}
+
+// HTTPError is returned by GetJSON and PostJSON when a response has a status code that is not accepted or its body
+// cannot be decoded. It wraps ErrorRequestFailed or ErrorDecodingRespBody respectively.
+type HTTPError struct {
+	StatusCode int    // The response status code.
+	Status     string // The response status line, e.g. "404 Not Found".
+	Body       string // The start of the response body.
+	RequestID  string // The request ID reported by the server, if any.
+	Err        error  // The underlying error.
+}
+
+// Error returns a description of the error including the status, request ID and the start of the body.
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("%s, status: %s", e.Err, e.Status)
+	if len(e.RequestID) > 0 {
+		msg += ", request id: " + e.RequestID
+	}
+	if len(e.Body) > 0 {
+		msg += ", body: " + e.Body
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// JSONOption configures a request made by GetJSON or PostJSON.
+type JSONOption func(*jsonOptions)
+
+// jsonOptions holds the settings applied by JSONOption functions.
+type jsonOptions struct {
+	strict bool
+	header Header
+}
+
+// Strict causes response fields that do not match a field of the result type to be treated as an error.
+func Strict() JSONOption {
+	return func(o *jsonOptions) {
+		o.strict = true
+	}
+}
+
+// RequestHeader sets header fields on the request.
+func RequestHeader(header Header) JSONOption {
+	return func(o *jsonOptions) {
+		for k, v := range header {
+			o.header[k] = v
+		}
+	}
+}
+
+// GetJSON sends a GET request and decodes the JSON response body into a value of type T as it is read. An HTTPError is
+// returned if the response status code is not accepted or the body cannot be decoded. An empty response body results
+// in the zero value of T. The Response returned does not hold the body unless decoding failed, in which case it holds
+// the start of it.
+func GetJSON[T any](ctx context.Context, c *Client, u *url.URL, opts ...JSONOption) (T, *Response, error) {
+	return doJSON[T](ctx, c, Request{Method: Get, URL: u}, opts)
+}
+
+// PostJSON sends a POST request with the value specified as a JSON body and decodes the JSON response body into a value
+// of type Out as it is read. See GetJSON.
+func PostJSON[In, Out any](ctx context.Context, c *Client, u *url.URL, in In, opts ...JSONOption) (Out, *Response, error) {
+	body, err := json.Marshal(in)
+	if err != nil {
+		var zero Out
+		return zero, nil, requestBodyError(err.Error())
+	}
+	return doJSON[Out](ctx, c, Request{Method: Post, URL: u, Body: body, ContentType: contentTypeJSON}, opts)
+}
+
+// doJSON sends a request and decodes the JSON response body.
+func doJSON[T any](ctx context.Context, c *Client, req Request, opts []JSONOption) (T, *Response, error) {
+	o := jsonOptions{header: Header{"Accept": contentTypeJSON}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	req.Header = o.header
+
+	var result T
+	resp, err := c.do(ctx, req, func(body io.Reader) error {
+		decoder := json.NewDecoder(body)
+		if o.strict {
+			decoder.DisallowUnknownFields()
+		}
+		if err := decoder.Decode(&result); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("%w: %w", ErrorDecodingRespBody, err)
+		}
+		return nil
+	})
+	if err == nil || resp == nil {
+		return result, resp, err
+	}
+
+	httpErr := &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status, Err: err, Body: snippet(resp.Body)}
+	if resp.decodeErr == nil {
+		httpErr.Err = ErrorRequestFailed
+	}
+	for _, key := range RequestIDHeaders {
+		if id := resp.Header.Get(key); len(id) > 0 {
+			httpErr.RequestID = id
+			break
+		}
+	}
+	var zero T
+	return zero, resp, httpErr
+}
+
+// snippet returns the start of a response body with sensitive values redacted.
+func snippet(body []byte) string {
+	if len(body) > snippetSize {
+		body = body[:snippetSize]
+	}
+	return logging.GetRedactor().RedactString(string(body))
+}
+
+// snippetWriter holds the first snippetSize bytes written to it.
+type snippetWriter struct {
+	data []byte
+}
+
+func (w *snippetWriter) Write(p []byte) (int, error) {
+	if remaining := snippetSize - len(w.data); remaining > 0 {
+		w.data = append(w.data, p[:min(remaining, len(p))]...)
+	}
+	return len(p), nil
+}
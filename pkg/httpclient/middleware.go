@@ -0,0 +1,36 @@
+package httpclient
+
+import "net/http"
+
+// Middleware wraps a RoundTripper, returning a RoundTripper that can modify requests and responses or handle them itself.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an adapter to allow the use of ordinary functions as RoundTrippers.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Chain returns the transport wrapped in the middleware, the first middleware is the outermost.
+func Chain(transport http.RoundTripper, mw ...Middleware) http.RoundTripper {
+	for i := len(mw) - 1; i >= 0; i-- {
+		transport = mw[i](transport)
+	}
+	return transport
+}
+
+// WithHeader returns middleware that sets a header on each request that does not already have it.
+func WithHeader(key, value string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if len(req.Header.Get(key)) > 0 {
+				return next.RoundTrip(req)
+			}
+			req = req.Clone(req.Context())
+			req.Header.Set(key, value)
+			return next.RoundTrip(req)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package httpclient
+
+import (
+	"net/http"
+	"slices"
+)
+
+// Option configures the requests made by a Client.
+type Option func(*options)
+
+// options holds the settings applied by Option functions.
+type options struct {
+	retry          RetryPolicy
+	middleware     []Middleware
+	acceptedStatus []int
+}
+
+// newOptions returns the default settings with the options applied.
+func newOptions(opts []Option) options {
+	o := options{retry: DefaultRetryPolicy()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) {
+		if policy.MaxAttempts < 1 {
+			policy.MaxAttempts = 1
+		}
+		o.retry = policy
+	}
+}
+
+// Use adds middleware that wraps the transport used to send requests, e.g. to add authentication, logging, metrics or
+// to replace the transport in tests. The first middleware specified is the outermost.
+func Use(mw ...Middleware) Option {
+	return func(o *options) {
+		o.middleware = append(o.middleware, mw...)
+	}
+}
+
+// WithAcceptedStatus sets the response status codes that indicate a request succeeded, by default any 2xx status code
+// is accepted.
+func WithAcceptedStatus(codes ...int) Option {
+	return func(o *options) {
+		o.acceptedStatus = codes
+	}
+}
+
+// accepted reports whether a status code indicates a request succeeded.
+func (o *options) accepted(code int) bool {
+	if len(o.acceptedStatus) > 0 {
+		return slices.Contains(o.acceptedStatus, code)
+	}
+	return code >= http.StatusOK && code < http.StatusMultipleChoices
+}
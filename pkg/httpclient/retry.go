@@ -0,0 +1,154 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultMaxAttempts    = 5
+	defaultMaxElapsed     = 2 * time.Minute
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+	defaultJitter         = 0.2
+)
+
+// RetryPolicy determines when and how often a failed request is retried.
+type RetryPolicy struct {
+	MaxAttempts      int           // The maximum number of attempts including the first, one disables retries.
+	MaxElapsed       time.Duration // The maximum time spent on a request including waiting between attempts, zero for no limit.
+	InitialBackoff   time.Duration // The wait before the first retry, doubled for each subsequent retry.
+	MaxBackoff       time.Duration // The maximum wait between attempts.
+	Jitter           float64       // The fraction of the wait that is randomised, between zero and one.
+	RetryStatusCodes []int         // The response status codes that are retried.
+}
+
+// DefaultRetryPolicy returns the policy used unless another is specified, it retries connection failures, timeouts and
+// responses with status 429, 502, 503 or 504 up to five attempts within two minutes.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultMaxAttempts,
+		MaxElapsed:     defaultMaxElapsed,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		Jitter:         defaultJitter,
+		RetryStatusCodes: []int{
+			http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// NoRetry returns a policy that makes a single attempt.
+func NoRetry() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// retryableStatus reports whether a response status code should be retried. Requests using methods that are not
+// idempotent are only retried if the status is 429 or 503, which indicate the server did not process the request, as
+// the server may have acted on a request answered with another status, e.g. a 502 or 504 from a proxy.
+func (p RetryPolicy) retryableStatus(method string, code int) bool {
+	if !slices.Contains(p.RetryStatusCodes, code) {
+		return false
+	}
+	return idempotent(method) || code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable
+}
+
+// retryableError reports whether an error returned when sending a request is transient. Errors caused by the request
+// context being cancelled or timing out are not retried. Requests using methods that are not idempotent, such as POST,
+// are only retried if the error occurred connecting to the server, before the request was sent, so that they are not
+// repeated.
+func (p RetryPolicy) retryableError(ctx context.Context, method string, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if !idempotent(method) {
+		return dialError(err)
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+	return false
+}
+
+// idempotent reports whether requests using a method can be repeated without changing their effect.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// dialError reports whether an error occurred connecting to the server, in which case the request was not sent.
+func dialError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && (opErr.Op == "dial" || opErr.Op == "proxyconnect")
+}
+
+// backoff returns the wait before the next attempt after the number of attempts made, using the Retry-After header of
+// the response if it has one.
+func (p RetryPolicy) backoff(attempts int, header http.Header) time.Duration {
+	if wait, ok := retryAfter(header.Get("Retry-After")); ok {
+		return wait
+	}
+	wait := float64(p.InitialBackoff) * math.Pow(2, float64(attempts-1)) //nolint: mnd
+	if p.MaxBackoff > 0 {
+		wait = math.Min(wait, float64(p.MaxBackoff))
+	}
+	if p.Jitter > 0 {
+		wait -= wait * p.Jitter * rand.Float64() //nolint: gosec
+	}
+	return time.Duration(wait)
+}
+
+// retryAfter parses a Retry-After header value, which is either a number of seconds or an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if len(value) == 0 {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// wait waits for the duration specified or until the context is done.
+func wait(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
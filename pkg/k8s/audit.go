@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/paul-carlton/goutils/pkg/logging"
+)
+
+// kubeUser returns the user the Kubernetes API server authenticates requests as, it is looked up once using a
+// SelfSubjectReview falling back to the user specified in the rest config.
+func (k *k8s) kubeUser() string {
+	k.userOnce.Do(func() {
+		if k.client != nil {
+			ctx, cancel := context.WithTimeout(k.o.Ctx, time.Second*10) //nolint: mnd
+			defer cancel()
+			review, err := k.client.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authv1.SelfSubjectReview{}, metav1.CreateOptions{})
+			if err == nil && review != nil && len(review.Status.UserInfo.Username) > 0 {
+				k.user = review.Status.UserInfo.Username
+				return
+			}
+		}
+		if k.config != nil {
+			if len(k.config.Impersonate.UserName) > 0 {
+				k.user = k.config.Impersonate.UserName
+				return
+			}
+			k.user = k.config.Username
+		}
+	})
+	return k.user
+}
+
+// audit records a mutating operation on a namespaced object in the audit log.
+func (k *k8s) audit(action, kind, name, namespace string, params map[string]any, err error) {
+	k.auditOutcome(action, kind, name, namespace, params, "", err)
+}
+
+// auditOutcome records a mutating operation on a namespaced object in the audit log with the outcome specified, which
+// is used if err is nil.
+func (k *k8s) auditOutcome(action, kind, name, namespace string, params map[string]any, outcome string, err error) {
+	if logging.GetAuditLog() == nil {
+		return
+	}
+	logging.Audit(k.o.Ctx, logging.AuditEvent{
+		Actor:   k.kubeUser(),
+		Action:  action,
+		Target:  fmt.Sprintf("%s/%s/%s", kind, namespace, name),
+		Params:  params,
+		Outcome: outcome,
+	}, err)
+}
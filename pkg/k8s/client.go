@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/url"
 	"os"
+	"sync"
 	"time"
 
 	kustomize "github.com/fluxcd/kustomize-controller/api/v1"
@@ -13,16 +14,13 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	apimeta "github.com/fluxcd/pkg/apis/meta"
-	zaplogfmt "github.com/sykesm/zap-logfmt"
-	uzap "go.uber.org/zap"
-	"go.uber.org/zap/zapcore"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/paul-carlton/goutils/pkg/logging"
 	"github.com/paul-carlton/goutils/pkg/miscutils"
@@ -38,6 +36,9 @@ type k8s struct {
 	cc     ctrlclient.Client
 	client kubernetes.Interface
 	config *rest.Config
+
+	userOnce sync.Once
+	user     string
 }
 
 type K8s interface {
@@ -124,24 +125,9 @@ func init() {
 	_ = kustomize.AddToScheme(scheme) //nolint: errcheck
 	_ = corev1.AddToScheme(scheme)    //nolint: errcheck
 
-	leveler := uzap.LevelEnablerFunc(func(level zapcore.Level) bool {
-		// Set the level fairly high since it's so verbose
-		return level >= zapcore.DPanicLevel
-	})
-	stackTraceLeveler := uzap.LevelEnablerFunc(func(_ zapcore.Level) bool {
-		// Attempt to suppress the stack traces in the logs since they are so verbose.
-		// The controller runtime seems to ignore this since the stack is still always printed.
-		return false
-	})
-	logfmtEncoder := zaplogfmt.NewEncoder(uzap.NewProductionEncoderConfig())
-	logger := zap.New(
-		zap.Level(leveler),
-		zap.StacktraceLevel(stackTraceLeveler),
-		zap.UseDevMode(false),
-		zap.WriteTo(os.Stdout),
-		zap.Encoder(logfmtEncoder))
-
+	logger := logging.LogrLogger(logging.NewLogger())
 	ctrllog.SetLogger(logger)
+	klog.SetLogger(logger)
 }
 
 func (k *k8s) SetCtrlClient(client ctrlclient.Client, ctrlScheme *runtime.Scheme) error {
@@ -1,58 +1,37 @@
 package k8s
 
 import (
-	"errors"
-	"io"
-	"os"
+	"bytes"
+	"log/slog"
 	"testing"
-	"time"
 
-	"github.com/paul-carlton/goutils/pkg/miscutils"
+	"github.com/paul-carlton/goutils/pkg/logging"
+	"github.com/paul-carlton/goutils/pkg/logging/logtest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // MockKubernetesClient is a mock implementation of kubernetes.Interface
 type MockKubernetesClient struct {
+	kubernetes.Interface
 	mock.Mock
 }
 
 // MockCtrlClient is a mock implementation of ctrlclient.Client
 type MockCtrlClient struct {
+	ctrlclient.Client
 	mock.Mock
 }
 
-// MockLogger is a mock implementation of a logger
-type MockLogger struct {
-	mock.Mock
-}
-
-func (m *MockLogger) Debug(msg string, keysAndValues ...interface{}) {
-	m.Called(msg, keysAndValues)
-}
-
-func (m *MockLogger) Info(msg string, keysAndValues ...interface{}) {
-	m.Called(msg, keysAndValues)
-}
-
-func (m *MockLogger) Error(msg string, keysAndValues ...interface{}) {
-	m.Called(msg, keysAndValues)
-}
-
 func TestNewK8s(t *testing.T) {
 	// Setup
-	mockLogger := &MockLogger{}
-	mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything).Return()
-	mockLogger.On("Debug", mock.Anything, mock.Anything).Return()
-
-	objParams := &miscutils.NewObjParams{
-		Log: mockLogger,
-	}
+	objParams, recorder := logtest.NewObjParams(slog.LevelDebug)
 
 	config := &rest.Config{}
 	mockClient := &MockKubernetesClient{}
@@ -78,6 +57,7 @@ func TestNewK8s(t *testing.T) {
 		// and file system access. We'll skip actual verification and just
 		// ensure it doesn't panic.
 		_, err := NewK8s(objParams, nil, mockCtrlClient, mockClient, scheme)
+		recorder.AssertLogged(t, slog.LevelDebug, "Failed to find In-Cluster Config, attempting to use KUBECONFIG")
 
 		// The error might be nil or not depending on the environment
 		// We're just making sure the function handles nil config gracefully
@@ -98,13 +78,7 @@ func TestNewK8s(t *testing.T) {
 
 func TestSetGetKubeConfig(t *testing.T) {
 	// Setup
-	mockLogger := &MockLogger{}
-	mockLogger.On("Debug", mock.Anything, mock.Anything).Return()
-	mockLogger.On("Error", mock.Anything, mock.Anything).Return()
-
-	objParams := &miscutils.NewObjParams{
-		Log: mockLogger,
-	}
+	objParams, _ := logtest.NewObjParams(nil)
 
 	k := &k8s{
 		o: objParams,
@@ -124,10 +98,7 @@ func TestSetGetKubeConfig(t *testing.T) {
 
 func TestSetGetKubeClient(t *testing.T) {
 	// Setup
-	mockLogger := &MockLogger{}
-	objParams := &miscutils.NewObjParams{
-		Log: mockLogger,
-	}
+	objParams, _ := logtest.NewObjParams(nil)
 
 	k := &k8s{
 		o: objParams,
@@ -152,10 +123,7 @@ func TestSetGetKubeClient(t *testing.T) {
 
 func TestSetGetCtrlClient(t *testing.T) {
 	// Setup
-	mockLogger := &MockLogger{}
-	objParams := &miscutils.NewObjParams{
-		Log: mockLogger,
-	}
+	objParams, _ := logtest.NewObjParams(nil)
 
 	k := &k8s{
 		o: objParams,
@@ -216,12 +184,7 @@ func TestScaleDeployment(t *testing.T) {
 /*
 func TestDeleteDeployment(t *testing.T) {
 	// Setup
-	mockLogger := &MockLogger{}
-	mockLogger.On("Debug", mock.Anything, mock.Anything).Return()
-
-	objParams := &miscutils.NewObjParams{
-		Log: mockLogger,
-	}
+	objParams, _ := logtest.NewObjParams(nil)
 
 	mockClient := &MockKubernetesClient{}
 	mockAppsV1 := &MockAppsV1Client{}
@@ -247,3 +210,17 @@ func TestDeleteDeployment(t *testing.T) {
 	mockDeployments.AssertExpectations(t)
 }
 */
+
+func TestDeleteDeploymentNotFound(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logging.SetAuditLog(logging.NewAuditLog(buf))
+	defer logging.SetAuditLog(nil)
+
+	objParams, recorder := logtest.NewObjParams(nil)
+	k := &k8s{o: objParams, client: fake.NewSimpleClientset(), config: &rest.Config{}}
+
+	require.NoError(t, k.DeleteDeployment("web", "default", 0, 0))
+	recorder.AssertLogged(t, slog.LevelError, "deployment not found")
+	assert.Contains(t, buf.String(), `"action":"DeleteDeployment"`)
+	assert.Contains(t, buf.String(), `"outcome":"not_found"`)
+}
@@ -13,15 +13,19 @@ import (
 	"github.com/paul-carlton/goutils/pkg/miscutils"
 )
 
-func (k *k8s) DeleteCronJob(name, namespace string, gracePeriod int64, waitFor time.Duration) error {
+func (k *k8s) DeleteCronJob(name, namespace string, gracePeriod int64, waitFor time.Duration) (err error) {
 	logging.TraceCall()
 	defer logging.TraceExit()
+	defer func() {
+		k.audit("DeleteCronJob", "cronjob", name, namespace,
+			map[string]any{"gracePeriod": gracePeriod, "waitFor": waitFor}, err)
+	}()
 
 	ctx, cancel := context.WithTimeout(k.o.Ctx, time.Second*60)
 	defer cancel()
 	dc := k.client.BatchV1().CronJobs(namespace)
 	do := k.getMetaV1DeleteOptions(gracePeriod)
-	if err := dc.Delete(ctx, name, do); err != nil {
+	if err = dc.Delete(ctx, name, do); err != nil {
 		return err
 	}
 	miscutils.LogInfo(k.o, "waiting for job deletion")
@@ -15,17 +15,23 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 )
 
-func (k *k8s) DeleteDeployment(name, namespace string, gracePeriod int64, waitFor time.Duration) error {
+func (k *k8s) DeleteDeployment(name, namespace string, gracePeriod int64, waitFor time.Duration) (err error) {
 	logging.TraceCall()
 	defer logging.TraceExit()
+	outcome := ""
+	defer func() {
+		k.auditOutcome("DeleteDeployment", "deployment", name, namespace,
+			map[string]any{"gracePeriod": gracePeriod, "waitFor": waitFor}, outcome, err)
+	}()
 
 	ctx, cancel := context.WithTimeout(k.o.Ctx, time.Minute*10)
 	defer cancel()
 	dc := k.client.AppsV1().Deployments(namespace)
 	do := k.getMetaV1DeleteOptions(gracePeriod)
-	if err := dc.Delete(ctx, name, do); err != nil {
+	if err = dc.Delete(ctx, name, do); err != nil {
 		if errors.IsNotFound(err) {
 			miscutils.LogError(k.o, "deployment not found")
+			outcome = logging.AuditNotFound
 			return nil
 		}
 		return err
@@ -108,9 +114,11 @@ func (k *k8s) ScaleDeployments(names []string, namespace string, replicas int32)
 	return nil
 }
 
-func (k *k8s) ScaleDeployment(name, namespace string, replicas int32) error {
+func (k *k8s) ScaleDeployment(name, namespace string, replicas int32) (err error) {
 	logging.TraceCall()
 	defer logging.TraceExit()
+	params := map[string]any{"replicas": replicas}
+	defer func() { k.audit("ScaleDeployment", "deployment", name, namespace, params, err) }()
 
 	ctx, cancel := context.WithTimeout(k.o.Ctx, time.Minute*10)
 	defer cancel()
@@ -119,13 +127,16 @@ func (k *k8s) ScaleDeployment(name, namespace string, replicas int32) error {
 	if err != nil {
 		return err
 	}
+	params["previousReplicas"] = s.Spec.Replicas
 	sc := *s
 	sc.Spec.Replicas = replicas
-	_, err = dc.UpdateScale(ctx,
+	updated, err := dc.UpdateScale(ctx,
 		name, &sc, metav1.UpdateOptions{})
 	if err != nil {
 		return err
 	}
+	k.o.Log.InfoContext(k.o.Ctx, "updated Deployment scale",
+		"object", logging.K8sObject(updated), "diff", logging.K8sDiff(s, updated))
 	depl, err := dc.Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return err
@@ -135,12 +146,13 @@ func (k *k8s) ScaleDeployment(name, namespace string, replicas int32) error {
 	return k.waitForReplicasToScale(name, namespace, selector, replicas)
 }
 
-func (k *k8s) RolloutRestartDeployment(name, namespace string) error {
+func (k *k8s) RolloutRestartDeployment(name, namespace string) (err error) {
 	logging.TraceCall()
 	defer logging.TraceExit()
+	defer func() { k.audit("RolloutRestartDeployment", "deployment", name, namespace, nil, err) }()
 
 	dc := k.client.AppsV1().Deployments(namespace)
 	data := fmt.Sprintf(`{"spec": {"template": {"metadata": {"annotations": {"kubectl.kubernetes.io/restartedAt": "%s"}}}}}`, time.Now().Format("20060102150405"))
-	_, err := dc.Patch(k.o.Ctx, name, k8stypes.StrategicMergePatchType, []byte(data), metav1.PatchOptions{})
+	_, err = dc.Patch(k.o.Ctx, name, k8stypes.StrategicMergePatchType, []byte(data), metav1.PatchOptions{})
 	return err
 }
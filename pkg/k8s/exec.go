@@ -54,9 +54,13 @@ func (k *k8s) ExecuteCommandWithOptions(pod, namespace, container string, comman
 	return k.ExecPod(&options)
 }
 
-func (k *k8s) ExecPod(options *ExecOptions) (string, string, error) {
+func (k *k8s) ExecPod(options *ExecOptions) (stdOut, stdErr string, err error) {
 	logging.TraceCall()
 	defer logging.TraceExit()
+	defer func() {
+		k.audit("ExecPod", "pod", options.PodName, options.Namespace,
+			map[string]any{"container": options.ContainerName, "command": options.Command}, err)
+	}()
 
 	req := k.client.CoreV1().RESTClient().Post().
 		Resource("pods").
@@ -75,7 +79,7 @@ func (k *k8s) ExecPod(options *ExecOptions) (string, string, error) {
 	}, ksScheme.ParameterCodec)
 
 	var stdout, stderr bytes.Buffer
-	err := k.execute("POST", req.URL(), options.Stdin, &stdout, &stderr, false)
+	err = k.execute("POST", req.URL(), options.Stdin, &stdout, &stderr, false)
 	if options.PreserveWhitespace {
 		return stdout.String(), stderr.String(), err
 	}
@@ -89,6 +89,11 @@ func (k *k8s) ResumeKustomization(kustomization *kustomize.Kustomization) error
 func (k *k8s) updateSuspendKustomization(kustomization *kustomize.Kustomization, suspend bool) (err error) {
 	logging.TraceCall()
 	defer logging.TraceExit()
+	action := "ResumeKustomization"
+	if suspend {
+		action = "SuspendKustomization"
+	}
+	defer func() { k.audit(action, "kustomization", kustomization.Name, kustomization.Namespace, nil, err) }()
 
 	if suspend {
 		miscutils.LogWarning(k.o, fmt.Sprintf("suspending Kustomization: %s, in namespace: %s",
@@ -97,10 +102,16 @@ func (k *k8s) updateSuspendKustomization(kustomization *kustomize.Kustomization,
 		miscutils.LogInfo(k.o, fmt.Sprintf("resuming Kustomization: %s, in namespace: %s",
 			kustomization.Name, kustomization.Namespace))
 	}
+	original := kustomization.DeepCopy()
 	kustomization.Spec.Suspend = suspend
 	ctx, cancel := context.WithTimeout(k.o.Ctx, time.Second*30)
 	defer cancel()
-	return k.cc.Update(ctx, kustomization)
+	if err = k.cc.Update(ctx, kustomization); err != nil {
+		return err
+	}
+	k.o.Log.InfoContext(k.o.Ctx, "updated Kustomization",
+		"object", logging.K8sObject(kustomization), "diff", logging.K8sDiff(original, kustomization))
+	return nil
 }
 
 func (k *k8s) CheckKustomzationStatus(kustomization *kustomize.Kustomization) (string, error) {
@@ -130,12 +141,15 @@ func (k *k8s) ReconcileKustomization(kustomization *kustomize.Kustomization, wai
 	return k.WaitForReconciledKustomization(kustomization, waitFor)
 }
 
-func (k *k8s) patchReconcileAnnotation(kustomization *kustomize.Kustomization) error {
+func (k *k8s) patchReconcileAnnotation(kustomization *kustomize.Kustomization) (err error) {
 	logging.TraceCall()
 	defer logging.TraceExit()
+	defer func() {
+		k.audit("ReconcileKustomization", "kustomization", kustomization.Name, kustomization.Namespace, nil, err)
+	}()
 
 	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{"reconcile.fluxcd.io/requestedAt": %q}}}`, time.Now().Format(time.RFC3339)))
-	err := k.cc.Patch(k.o.Ctx, kustomization, ctrlclient.RawPatch(k8stypes.MergePatchType, patch))
+	err = k.cc.Patch(k.o.Ctx, kustomization, ctrlclient.RawPatch(k8stypes.MergePatchType, patch))
 	if err != nil {
 		miscutils.LogError(k.o, fmt.Sprintf("Error patching annotation for Kustomization: %s", kustomization.Name))
 		miscutils.LogError(k.o, fmt.Sprintf("Error: %s", err))
@@ -204,15 +218,19 @@ func (k *k8s) createKustomizationSpec(ksPath, sourceRepo string, postBuild *kust
 	return spec
 }
 
-func (k *k8s) DeleteKustomization(kustomization *kustomize.Kustomization, gracePeriod int64, waitFor time.Duration) error {
+func (k *k8s) DeleteKustomization(kustomization *kustomize.Kustomization, gracePeriod int64, waitFor time.Duration) (err error) {
 	logging.TraceCall()
 	defer logging.TraceExit()
+	defer func() {
+		k.audit("DeleteKustomization", "kustomization", kustomization.Name, kustomization.Namespace,
+			map[string]any{"gracePeriod": gracePeriod, "waitFor": waitFor}, err)
+	}()
 
 	ctx, cancel := context.WithTimeout(k.o.Ctx, time.Minute*10)
 	defer cancel()
 
 	options := k.getCtrlDeleteOptions(gracePeriod)
-	if err := k.cc.Delete(ctx, kustomization, options); err != nil {
+	if err = k.cc.Delete(ctx, kustomization, options); err != nil {
 		return err
 	}
 	return k.WaitForKustomizationDeletion(kustomization, waitFor)
@@ -128,11 +128,13 @@ func (k *k8s) WaitForPodDeletion(name, namespace string) error {
 	return nil
 }
 
-func (k *k8s) DeletePod(name, namespace string, grace int64) error {
+func (k *k8s) DeletePod(name, namespace string, grace int64) (err error) {
+	defer func() { k.audit("DeletePod", "pod", name, namespace, map[string]any{"gracePeriod": grace}, err) }()
+
 	ctx, cancel := context.WithTimeout(k.o.Ctx, time.Minute*10)
 	defer cancel()
 	pc := k.client.CoreV1().Pods(namespace)
-	err := pc.Delete(ctx, name, metav1.DeleteOptions{GracePeriodSeconds: &grace})
+	err = pc.Delete(ctx, name, metav1.DeleteOptions{GracePeriodSeconds: &grace})
 
 	// add wait.
 	return err
@@ -0,0 +1,220 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultAsyncQueueSize is the number of records queued by an AsyncHandler if no size is specified.
+	DefaultAsyncQueueSize = 1024
+
+	// asyncFatalFlushTimeout is the time allowed for an AsyncHandler to write its queued records when Fatal is called.
+	asyncFatalFlushTimeout = 5 * time.Second
+)
+
+// OverflowPolicy determines what an AsyncHandler does when its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits for space in the queue.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest queued record to make space.
+	OverflowDropOldest
+	// OverflowDropDebug discards records at DEBUG level and below, waiting for space for other records.
+	OverflowDropDebug
+)
+
+// AsyncConfig holds the configuration of an AsyncHandler.
+type AsyncConfig struct {
+	QueueSize int            // The maximum number of records queued, defaults to DefaultAsyncQueueSize.
+	Overflow  OverflowPolicy // What to do when the queue is full, defaults to OverflowBlock.
+}
+
+// AsyncStats holds the counts reported by an AsyncHandler.
+type AsyncStats struct {
+	Queued  int    // The number of records waiting to be written.
+	Dropped uint64 // The number of records discarded because the queue was full.
+	Errors  uint64 // The number of records the next handler failed to write.
+}
+
+// asyncEntry is a queued record together with the handler and context it was logged with, or a flush request.
+type asyncEntry struct {
+	ctx     context.Context //nolint: containedctx
+	handler slog.Handler
+	record  slog.Record
+	flushed chan struct{}
+}
+
+// asyncQueue is the queue and worker shared by an AsyncHandler and the handlers derived from it.
+type asyncQueue struct {
+	config  AsyncConfig
+	queue   chan asyncEntry
+	done    chan struct{}
+	mu      sync.RWMutex
+	closed  bool
+	once    sync.Once
+	dropped atomic.Uint64
+	errors  atomic.Uint64
+	unhook  func()
+}
+
+// AsyncHandler is a slog.Handler that queues records and writes them to the next handler in a background goroutine.
+type AsyncHandler struct {
+	next  slog.Handler
+	queue *asyncQueue
+}
+
+// NewAsyncHandler returns a handler that passes records to the next handler asynchronously. Close should be called
+// before the process exits to write any queued records, the queue is also flushed if Fatal is called.
+func NewAsyncHandler(next slog.Handler, config AsyncConfig) *AsyncHandler {
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultAsyncQueueSize
+	}
+	q := &asyncQueue{config: config, queue: make(chan asyncEntry, config.QueueSize), done: make(chan struct{})}
+	q.unhook = OnFatal(func(ctx context.Context, _ slog.Record) {
+		ctx, cancel := context.WithTimeout(ctx, asyncFatalFlushTimeout)
+		defer cancel()
+		_ = q.flush(ctx) //nolint: errcheck
+	})
+	go q.run()
+	return &AsyncHandler{next: next, queue: q}
+}
+
+// NewAsyncLoggerTo returns a logger writing to provided writer asynchronously using the format selected by LOG_FORMAT,
+// together with its AsyncHandler so the caller can flush and close it.
+func NewAsyncLoggerTo(out io.Writer, config AsyncConfig) (*slog.Logger, *AsyncHandler) {
+	handler := NewAsyncHandler(formatHandler(out, GetLogFormat(), logSource), config)
+	return slog.New(wrapHandler(handler)), handler
+}
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle queues the record, applying the overflow policy if the queue is full. Records logged after Close are
+// written synchronously.
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	q := h.queue
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return h.next.Handle(ctx, r)
+	}
+
+	entry := asyncEntry{ctx: context.WithoutCancel(ctx), handler: h.next, record: r.Clone()}
+	switch {
+	case q.config.Overflow == OverflowDropOldest:
+		q.enqueueDropOldest(entry)
+	case q.config.Overflow == OverflowDropDebug && r.Level <= slog.LevelDebug:
+		select {
+		case q.queue <- entry:
+		default:
+			q.dropped.Add(1)
+		}
+	default:
+		q.queue <- entry
+	}
+	return nil
+}
+
+// WithAttrs returns a new AsyncHandler sharing the queue whose next handler has the given attributes.
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{next: h.next.WithAttrs(attrs), queue: h.queue}
+}
+
+// WithGroup returns a new AsyncHandler sharing the queue whose next handler has the given group.
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &AsyncHandler{next: h.next.WithGroup(name), queue: h.queue}
+}
+
+// Flush waits until the records queued before it was called have been written or the context is done.
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	return h.queue.flush(ctx)
+}
+
+// Close writes the queued records and stops the background goroutine, it can be called more than once.
+func (h *AsyncHandler) Close() error {
+	q := h.queue
+	q.once.Do(func() {
+		q.unhook()
+		q.mu.Lock()
+		q.closed = true
+		close(q.queue)
+		q.mu.Unlock()
+	})
+	<-q.done
+	return nil
+}
+
+// Stats returns the number of records queued, dropped and that failed to be written.
+func (h *AsyncHandler) Stats() AsyncStats {
+	return AsyncStats{Queued: len(h.queue.queue), Dropped: h.queue.dropped.Load(), Errors: h.queue.errors.Load()}
+}
+
+// run writes queued records until the queue is closed.
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	for entry := range q.queue {
+		if entry.flushed != nil {
+			close(entry.flushed)
+			continue
+		}
+		if err := entry.handler.Handle(entry.ctx, entry.record); err != nil {
+			q.errors.Add(1)
+		}
+	}
+}
+
+// enqueueDropOldest queues an entry, discarding the oldest queued records until there is space.
+func (q *asyncQueue) enqueueDropOldest(entry asyncEntry) {
+	for {
+		select {
+		case q.queue <- entry:
+			return
+		default:
+		}
+		select {
+		case oldest := <-q.queue:
+			if oldest.flushed != nil {
+				close(oldest.flushed)
+				continue
+			}
+			q.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// flush queues a flush request and waits for the worker to reach it.
+func (q *asyncQueue) flush(ctx context.Context) error {
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return nil
+	}
+	flushed := make(chan struct{})
+	select {
+	case q.queue <- asyncEntry{flushed: flushed}:
+		q.mu.RUnlock()
+	case <-ctx.Done():
+		q.mu.RUnlock()
+		return ctx.Err()
+	}
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
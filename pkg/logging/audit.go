@@ -0,0 +1,326 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// AuditSuccess is the outcome of an audited operation that completed without error.
+	AuditSuccess = "success"
+	// AuditFailure is the outcome of an audited operation that returned an error.
+	AuditFailure = "failure"
+	// AuditNotFound is the outcome of an audited operation that had no effect because its target did not exist.
+	AuditNotFound = "not_found"
+
+	// auditLogFileEnvVar is the environmental variable name used to set the file audit records are appended to.
+	auditLogFileEnvVar = "AUDIT_LOG_FILE"
+
+	auditMaxLineSize = 1024 * 1024
+)
+
+// ErrAuditTampered is wrapped by the errors returned when an audit log fails verification.
+var ErrAuditTampered = errors.New("audit log verification failed")
+
+var (
+	auditLog     atomic.Pointer[AuditLog] //nolint: gochecknoglobals
+	auditEnvOnce sync.Once                //nolint: gochecknoglobals
+	auditUser    = sync.OnceValue(osUser) //nolint: gochecknoglobals
+)
+
+// AuditEvent describes a mutating operation to be recorded in the audit log.
+type AuditEvent struct {
+	Actor  string         // The identity the operation was performed as, e.g. kube user, AWS caller ARN or GitHub login.
+	Action string         // The operation, e.g. DeleteDeployment.
+	Target string         // The object the operation was applied to.
+	Params map[string]any // The parameters of the operation, sensitive values are redacted.
+	DryRun bool           // The operation was not actually performed.
+	// The outcome of an operation that did not return an error, e.g. AuditNotFound, defaults to AuditSuccess.
+	Outcome string
+}
+
+// AuditRecord is a single entry in the audit log. Each record holds the hash of the previous record so that changes,
+// insertions and deletions can be detected by VerifyAuditLog.
+type AuditRecord struct {
+	Seq      uint64          `json:"seq"`
+	Time     time.Time       `json:"time"`
+	User     string          `json:"user,omitempty"`
+	Actor    string          `json:"actor,omitempty"`
+	Action   string          `json:"action"`
+	Target   string          `json:"target,omitempty"`
+	Params   json.RawMessage `json:"params,omitempty"`
+	DryRun   bool            `json:"dry_run"`
+	Outcome  string          `json:"outcome"`
+	Error    string          `json:"error,omitempty"`
+	PrevHash string          `json:"prev_hash"`
+	Hash     string          `json:"hash"`
+}
+
+// AuditLog writes hash chained audit records to a writer, one JSON record per line. It is safe for concurrent use.
+type AuditLog struct {
+	mu     sync.Mutex
+	out    io.Writer
+	closer io.Closer
+	seq    uint64
+	prev   string
+}
+
+// NewAuditLog returns an AuditLog that starts a new hash chain on the writer.
+func NewAuditLog(out io.Writer) *AuditLog {
+	return &AuditLog{out: out}
+}
+
+// OpenAuditFile returns an AuditLog appending to a file. If the file already contains records they are verified and the
+// hash chain is continued from the last record.
+func OpenAuditFile(path string) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, logFileMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %s, error: %w", path, err)
+	}
+	last, _, err := verifyAuditLog(file, nil)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%w: %s", err, path)
+	}
+	return &AuditLog{out: file, closer: file, seq: last.Seq, prev: last.Hash}, nil
+}
+
+// SetAuditLog sets the audit log used by Audit, nil disables auditing.
+func SetAuditLog(a *AuditLog) {
+	auditEnvOnce.Do(func() {})
+	auditLog.Store(a)
+}
+
+// GetAuditLog returns the audit log used by Audit. If none has been set and AUDIT_LOG_FILE is set, the file it names is
+// opened on first use.
+func GetAuditLog() *AuditLog {
+	auditEnvOnce.Do(openAuditEnvFile)
+	return auditLog.Load()
+}
+
+// openAuditEnvFile opens the audit log file named by AUDIT_LOG_FILE, if set.
+func openAuditEnvFile() {
+	path, ok := os.LookupEnv(auditLogFileEnvVar)
+	if !ok || len(path) == 0 {
+		return
+	}
+	a, err := OpenAuditFile(path)
+	if err != nil {
+		internalLogger().Error("failed to open audit log", "error", err.Error())
+		return
+	}
+	auditLog.Store(a)
+}
+
+// Audit records the outcome of a mutating operation in the audit log, it does nothing if no audit log is set.
+// Failures to write the record are logged to the package log output.
+func Audit(ctx context.Context, event AuditEvent, err error) {
+	a := GetAuditLog()
+	if a == nil {
+		return
+	}
+	if e := a.Record(event, err); e != nil {
+		internalLogger().ErrorContext(ctx, "failed to write audit record", "action", event.Action, "error", e.Error())
+	}
+}
+
+// Record writes an audit record for the event, the outcome is a failure if err is not nil.
+func (a *AuditLog) Record(event AuditEvent, err error) error {
+	record := AuditRecord{
+		Time:    time.Now().UTC(),
+		User:    auditUser(),
+		Actor:   event.Actor,
+		Action:  event.Action,
+		Target:  event.Target,
+		DryRun:  event.DryRun,
+		Outcome: cmp.Or(event.Outcome, AuditSuccess),
+	}
+	if err != nil {
+		record.Outcome = AuditFailure
+		record.Error = GetRedactor().RedactString(err.Error())
+	}
+	if len(event.Params) > 0 {
+		params, e := json.Marshal(redactParams(event.Params))
+		if e != nil {
+			return fmt.Errorf("failed to marshal audit parameters, error: %w", e)
+		}
+		record.Params = params
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	record.Seq = a.seq + 1
+	record.PrevHash = a.prev
+	hash, e := record.hash()
+	if e != nil {
+		return e
+	}
+	record.Hash = hash
+
+	line, e := json.Marshal(record)
+	if e != nil {
+		return fmt.Errorf("failed to marshal audit record, error: %w", e)
+	}
+	if _, e := a.out.Write(append(line, '\n')); e != nil {
+		return fmt.Errorf("failed to write audit record, error: %w", e)
+	}
+	a.seq, a.prev = record.Seq, record.Hash
+	return nil
+}
+
+// Head returns the sequence number and hash of the last record written. Storing these outside the audit log, e.g. in a
+// separate system after each operation, allows VerifyAuditLogHead to detect the log being rewritten, which the hash chain
+// alone cannot detect as the hashes can be recomputed.
+func (a *AuditLog) Head() (uint64, string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.seq, a.prev
+}
+
+// Close closes the file opened by OpenAuditFile, it does nothing for an AuditLog created using NewAuditLog.
+func (a *AuditLog) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// VerifyAuditLog checks the sequence numbers and hash chain of the audit records read from r. It returns the number of
+// records verified and an error wrapping ErrAuditTampered if a record has been changed, inserted or removed. Records
+// removed from the end of the log cannot be detected, the count can be compared with a previously verified count.
+func VerifyAuditLog(r io.Reader) (int, error) {
+	_, count, err := verifyAuditLog(r, nil)
+	return count, err
+}
+
+// VerifyAuditLogHead is the same as VerifyAuditLog but also checks that the record with the sequence number specified
+// has the hash specified, as returned by Head when the record was written. This detects records that have been changed
+// and had the hashes of the following records recomputed, and records removed from the end of the log.
+func VerifyAuditLogHead(r io.Reader, seq uint64, hash string) (int, error) {
+	found := false
+	_, count, err := verifyAuditLog(r, func(record AuditRecord) error {
+		if record.Seq != seq {
+			return nil
+		}
+		found = true
+		if record.Hash != hash {
+			return fmt.Errorf("%w: record %d does not match the head hash", ErrAuditTampered, record.Seq)
+		}
+		return nil
+	})
+	if err == nil && !found && seq > 0 {
+		err = fmt.Errorf("%w: record %d not found", ErrAuditTampered, seq)
+	}
+	return count, err
+}
+
+// verifyAuditLog verifies the audit records read from r, calling check, if specified, for each record. It returns the
+// last record and the number of records verified.
+func verifyAuditLog(r io.Reader, check func(AuditRecord) error) (AuditRecord, int, error) {
+	last := AuditRecord{}
+	count := 0
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), auditMaxLineSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		record := AuditRecord{}
+		if err := json.Unmarshal(line, &record); err != nil {
+			return last, count, fmt.Errorf("%w: record %d is not valid JSON, error: %s", ErrAuditTampered, count+1, err)
+		}
+		if record.Seq != last.Seq+1 {
+			return last, count, fmt.Errorf("%w: record %d has sequence number %d", ErrAuditTampered, count+1, record.Seq)
+		}
+		if record.PrevHash != last.Hash {
+			return last, count, fmt.Errorf("%w: record %d does not follow the previous record", ErrAuditTampered, record.Seq)
+		}
+		hash, err := record.hash()
+		if err != nil {
+			return last, count, err
+		}
+		if hash != record.Hash {
+			return last, count, fmt.Errorf("%w: record %d has been modified", ErrAuditTampered, record.Seq)
+		}
+		if check != nil {
+			if err := check(record); err != nil {
+				return last, count, err
+			}
+		}
+		last = record
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return last, count, fmt.Errorf("failed to read audit log, error: %w", err)
+	}
+	return last, count, nil
+}
+
+// hash returns the SHA-256 hash of the record with the hash field empty.
+func (r AuditRecord) hash() (string, error) {
+	r.Hash = ""
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal audit record, error: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// redactParams returns a copy of the parameters with the values of sensitive keys and text redacted.
+func redactParams(params map[string]any) map[string]any {
+	r := GetRedactor()
+	redacted := make(map[string]any, len(params))
+	for key, value := range params {
+		if r.IsSensitiveKey(key) {
+			redacted[key] = RedactedValue
+			continue
+		}
+		redacted[key] = r.redactValue(normalizeParam(value))
+	}
+	return redacted
+}
+
+// normalizeParam converts a parameter to the types produced by unmarshalling JSON so it can be redacted.
+func normalizeParam(value any) any {
+	switch v := value.(type) {
+	case string, bool, int, int32, int64, uint, uint32, uint64, float64, nil:
+		return v
+	case time.Duration:
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return string(data)
+	}
+	return normalized
+}
+
+// osUser returns the name of the user running the process.
+func osUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,295 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+const (
+	// HighlightKey is the attribute key used to request a message is highlighted by the console handler.
+	HighlightKey = "highlight"
+
+	// noColorEnvVar is the environmental variable that disables colour output when set to a non empty value.
+	noColorEnvVar = "NO_COLOR"
+
+	consoleTimeFormat = "15:04:05.000"
+	levelNameWidth    = 5
+)
+
+// consoleColors holds the colours used for each level, the highlight colour and the source colour.
+var consoleColors = struct { //nolint: gochecknoglobals
+	trace, debug, info, warn, err, fatal, highlight, faint *color.Color
+}{
+	trace:     enabledColor(color.FgMagenta),
+	debug:     enabledColor(color.FgCyan),
+	info:      enabledColor(color.FgGreen),
+	warn:      enabledColor(color.FgYellow),
+	err:       enabledColor(color.FgRed),
+	fatal:     enabledColor(color.Bold, color.FgRed),
+	highlight: enabledColor(color.Bold, color.FgBlue),
+	faint:     enabledColor(color.Faint),
+}
+
+// ConsoleHandler is a slog.Handler that writes human readable lines, colouring the level, message and attributes
+// according to the level when writing to a terminal. The ReplaceAttr function of its options is applied to the built
+// in and record attributes in the same way as the slog handlers.
+type ConsoleHandler struct {
+	out    io.Writer
+	mu     *sync.Mutex
+	opts   slog.HandlerOptions
+	color  bool
+	attrs  []slog.Attr
+	groups []string
+	prefix string
+}
+
+// Highlight returns an attribute that causes the console handler to highlight a message.
+func Highlight() slog.Attr {
+	return slog.Bool(HighlightKey, true)
+}
+
+// NewConsoleHandler returns a handler writing to out, colour is used if out is a terminal and NO_COLOR is not set.
+func NewConsoleHandler(out io.Writer, opts *slog.HandlerOptions) *ConsoleHandler {
+	h := &ConsoleHandler{out: out, mu: &sync.Mutex{}, color: useColor(out)}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// NewConsoleLogger returns a console logger writing to provided writer.
+func NewConsoleLogger(out io.Writer) *slog.Logger {
+	return slog.New(wrapHandler(formatHandler(out, FormatConsole, logSource)))
+}
+
+// useColor reports whether colour output should be written to out.
+func useColor(out io.Writer) bool {
+	if len(os.Getenv(noColorEnvVar)) > 0 {
+		return false
+	}
+	file, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(file.Fd()) || isatty.IsCygwinTerminal(file.Fd())
+}
+
+// Enabled reports whether the handler handles records at the given level.
+func (h *ConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle writes the record as a single line.
+func (h *ConsoleHandler) Handle(_ context.Context, r slog.Record) error {
+	levelColor := h.levelColor(r.Level)
+	msgColor := levelColor
+	if r.Level == slog.LevelInfo {
+		msgColor = nil
+	}
+
+	buf := &bytes.Buffer{}
+	if !r.Time.IsZero() {
+		if text, ok := h.builtin(slog.Time(slog.TimeKey, r.Time)); ok {
+			buf.WriteString(text)
+			buf.WriteByte(' ')
+		}
+	}
+	if text, ok := h.builtin(slog.Any(slog.LevelKey, r.Level)); ok {
+		buf.WriteString(h.paint(levelColor, fmt.Sprintf("%-*s", levelNameWidth, text)))
+	}
+
+	attrs := append([]slog.Attr{}, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == HighlightKey && a.Value.Resolve().Kind() == slog.KindBool {
+			if a.Value.Resolve().Bool() {
+				msgColor = consoleColors.highlight
+			}
+			return true
+		}
+		attrs = append(attrs, h.prefixed(h.groups, a))
+		return true
+	})
+
+	if text, ok := h.builtin(slog.String(slog.MessageKey, r.Message)); ok {
+		buf.WriteByte(' ')
+		buf.WriteString(h.paint(msgColor, text))
+	}
+
+	for _, a := range attrs {
+		h.appendAttr(buf, levelColor, "", a)
+	}
+
+	if h.opts.AddSource && r.PC != 0 {
+		if text, ok := h.builtin(slog.Any(slog.SourceKey, sourceOf(r.PC))); ok {
+			buf.WriteByte(' ')
+			buf.WriteString(h.paint(consoleColors.faint, text))
+		}
+	}
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.out.Write(buf.Bytes())
+	return err
+}
+
+// WithAttrs returns a new ConsoleHandler whose output includes the given attributes.
+func (h *ConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler := *h
+	handler.attrs = append([]slog.Attr{}, h.attrs...)
+	for _, a := range attrs {
+		handler.attrs = append(handler.attrs, h.prefixed(h.groups, a))
+	}
+	return &handler
+}
+
+// WithGroup returns a new ConsoleHandler that prefixes the keys of subsequent attributes with the group name.
+func (h *ConsoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	handler := *h
+	handler.groups = append(append([]string{}, h.groups...), name)
+	handler.prefix = h.prefix + name + "."
+	return &handler
+}
+
+// replace applies the ReplaceAttr function of the options, if there is one, to an attribute.
+func (h *ConsoleHandler) replace(groups []string, a slog.Attr) slog.Attr {
+	if h.opts.ReplaceAttr == nil {
+		return a
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		return h.opts.ReplaceAttr(groups, a)
+	}
+	if len(a.Key) > 0 {
+		groups = append(append([]string{}, groups...), a.Key)
+	}
+	attrs := make([]slog.Attr, 0, len(a.Value.Group()))
+	for _, attr := range a.Value.Group() {
+		attrs = append(attrs, h.replace(groups, attr))
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+}
+
+// builtin returns the text written for a built in attribute after applying the ReplaceAttr function, reporting false
+// if the attribute was removed.
+func (h *ConsoleHandler) builtin(a slog.Attr) (string, bool) {
+	if h.opts.ReplaceAttr == nil {
+		a = setSourceName(setLogLevelName(a))
+	} else {
+		a = h.opts.ReplaceAttr(nil, a)
+	}
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return "", false
+	}
+	switch a.Value.Kind() { //nolint: exhaustive
+	case slog.KindTime:
+		return a.Value.Time().Format(consoleTimeFormat), true
+	case slog.KindAny:
+		switch value := a.Value.Any().(type) {
+		case slog.Level:
+			return LevelName(value), true
+		case *slog.Source:
+			return fmt.Sprintf("%s:%d", value.File, value.Line), true
+		}
+	}
+	return a.Value.String(), true
+}
+
+// prefixed returns the attribute, after applying the ReplaceAttr function, with its key prefixed by the groups.
+func (h *ConsoleHandler) prefixed(groups []string, a slog.Attr) slog.Attr {
+	a = h.replace(groups, a)
+	if len(h.prefix) > 0 && len(a.Key) > 0 {
+		a.Key = h.prefix + a.Key
+	}
+	return a
+}
+
+// appendAttr writes an attribute as key=value with the key in the level colour, flattening groups into dot separated keys.
+func (h *ConsoleHandler) appendAttr(buf *bytes.Buffer, keyColor *color.Color, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	key := a.Key
+	if len(prefix) > 0 {
+		key = prefix + "." + key
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		for _, attr := range a.Value.Group() {
+			h.appendAttr(buf, keyColor, key, attr)
+		}
+		return
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(h.paint(keyColor, key+"="))
+	buf.WriteString(quoteValue(a.Value.String()))
+}
+
+// levelColor returns the colour used for a level.
+func (h *ConsoleHandler) levelColor(level slog.Level) *color.Color {
+	switch {
+	case level >= LevelFatal:
+		return consoleColors.fatal
+	case level >= slog.LevelError:
+		return consoleColors.err
+	case level >= slog.LevelWarn:
+		return consoleColors.warn
+	case level >= slog.LevelInfo:
+		return consoleColors.info
+	case level >= slog.LevelDebug:
+		return consoleColors.debug
+	default:
+		return consoleColors.trace
+	}
+}
+
+// paint returns the text in the colour specified if colour output is enabled.
+func (h *ConsoleHandler) paint(c *color.Color, text string) string {
+	if !h.color || c == nil {
+		return text
+	}
+	return c.Sprint(text)
+}
+
+// enabledColor returns a colour that is applied regardless of the fatih/color terminal detection, the console handler
+// decides whether to use colour based on its own writer.
+func enabledColor(attrs ...color.Attribute) *color.Color {
+	c := color.New(attrs...)
+	c.EnableColor()
+	return c
+}
+
+// quoteValue quotes a value if it is empty or contains spaces, quotes or control characters.
+func quoteValue(value string) string {
+	if value == "" || strings.ContainsFunc(value, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r)
+	}) {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// sourceOf returns the source location for a program counter.
+func sourceOf(pc uintptr) *slog.Source {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return &slog.Source{Function: frame.Function, File: frame.File, Line: frame.Line}
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+const (
+	// RunIDKey is the attribute key used for the run identifier of a tool or job.
+	RunIDKey = "run_id"
+	// ClusterKey is the attribute key used for the cluster name.
+	ClusterKey = "cluster"
+	// NamespaceKey is the attribute key used for the kubernetes namespace.
+	NamespaceKey = "namespace"
+	// WorkflowRunIDKey is the attribute key used for the GitHub workflow run identifier.
+	WorkflowRunIDKey = "workflow_run_id"
+	// UserKey is the attribute key used for the user requesting an operation.
+	UserKey = "user"
+)
+
+// ctxAttrsKey is the context key used to store log attributes.
+type ctxAttrsKey struct{}
+
+// ContextWith returns a context containing the supplied attributes in addition to any already held by the parent context.
+// The arguments are key/value pairs and/or slog.Attr values as accepted by slog.Logger.Info. Handlers created by this
+// package add the attributes to every record logged using the context, e.g. via slog.Logger.InfoContext.
+func ContextWith(ctx context.Context, args ...any) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	attrs := argsToAttrs(args)
+	if len(attrs) == 0 {
+		return ctx
+	}
+	existing := AttrsFromContext(ctx)
+	combined := make([]slog.Attr, 0, len(existing)+len(attrs))
+	combined = append(combined, existing...)
+	combined = append(combined, attrs...)
+	return context.WithValue(ctx, ctxAttrsKey{}, combined)
+}
+
+// AttrsFromContext returns the log attributes held by a context.
+func AttrsFromContext(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(ctxAttrsKey{}).([]slog.Attr) //nolint: errcheck
+	return attrs
+}
+
+// argsToAttrs converts key/value pairs and slog.Attr values to a slice of attributes.
+func argsToAttrs(args []any) []slog.Attr {
+	record := slog.Record{}
+	record.Add(args...)
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
+
+// ContextHandler is a slog.Handler that adds the attributes held by the context to each record.
+type ContextHandler struct {
+	next slog.Handler
+}
+
+// NewContextHandler returns a handler that adds context attributes to records before passing them to the next handler.
+func NewContextHandler(next slog.Handler) *ContextHandler {
+	return &ContextHandler{next: next}
+}
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the context attributes to the record and passes it to the next handler.
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs := AttrsFromContext(ctx); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a new ContextHandler whose next handler has the given attributes.
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewContextHandler(h.next.WithAttrs(attrs))
+}
+
+// WithGroup returns a new ContextHandler whose next handler has the given group.
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return NewContextHandler(h.next.WithGroup(name))
+}
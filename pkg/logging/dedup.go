@@ -0,0 +1,235 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// RepeatedKey is the attribute key used for the number of identical records collapsed into one.
+	RepeatedKey = "repeated"
+
+	// defaultDedupInterval is the period over which sampling and rate limits apply if no interval is specified.
+	defaultDedupInterval = time.Second
+)
+
+// DedupConfig holds the configuration of a DedupHandler.
+type DedupConfig struct {
+	Window     time.Duration  // Identical records within this period are collapsed, zero disables deduplication.
+	Interval   time.Duration  // The period over which sampling and rate limits apply, defaults to one second.
+	First      int            // The number of records with the same message written each interval before sampling, zero disables sampling.
+	Thereafter int            // After First, write every Thereafter-th record with the same message, zero drops them.
+	RateLimits map[string]int // The maximum number of records written each interval for specific messages, at any level.
+	SampleAll  bool           // Apply sampling to WARN and ERROR records, which are otherwise only subject to rate limits.
+}
+
+// dedupEntry tracks the identical records seen during a window.
+type dedupEntry struct {
+	ctx     context.Context //nolint: containedctx
+	handler slog.Handler
+	record  slog.Record
+	count   int
+	timer   *time.Timer
+}
+
+// dedupCounter counts the records with a message during an interval.
+type dedupCounter struct {
+	start time.Time
+	count int
+}
+
+// dedupState is shared by a DedupHandler and the handlers derived from it.
+type dedupState struct {
+	config   DedupConfig
+	mu       sync.Mutex
+	entries  map[string]*dedupEntry
+	counters map[string]*dedupCounter
+	swept    time.Time
+	dropped  atomic.Uint64
+}
+
+// DedupHandler is a slog.Handler that collapses repeated records and applies per-message sampling and rate limits.
+type DedupHandler struct {
+	next  slog.Handler
+	key   string
+	state *dedupState
+}
+
+// NewDedupHandler returns a handler that writes the first of a series of identical records, those with the same level,
+// message and attributes, and collapses the rest within the window into a single record with a repeated=N attribute
+// written when the window ends. Records are first sampled and rate limited by message as configured.
+func NewDedupHandler(next slog.Handler, config DedupConfig) *DedupHandler {
+	if config.Interval <= 0 {
+		config.Interval = defaultDedupInterval
+	}
+	return &DedupHandler{
+		next:  next,
+		state: &dedupState{config: config, entries: map[string]*dedupEntry{}, counters: map[string]*dedupCounter{}},
+	}
+}
+
+// NewDedupLoggerTo returns a logger writing to provided writer using the format selected by LOG_FORMAT that collapses
+// repeated records, together with its DedupHandler so the caller can flush it.
+func NewDedupLoggerTo(out io.Writer, config DedupConfig) (*slog.Logger, *DedupHandler) {
+	handler := NewDedupHandler(formatHandler(out, GetLogFormat(), logSource), config)
+	return slog.New(wrapHandler(handler)), handler
+}
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *DedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle passes the record to the next handler unless it is sampled out, rate limited or a repeat of a recent record.
+func (h *DedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	s := h.state
+	s.mu.Lock()
+
+	if !s.allow(r.Message, r.Level) {
+		s.mu.Unlock()
+		s.dropped.Add(1)
+		return nil
+	}
+
+	if s.config.Window <= 0 {
+		s.mu.Unlock()
+		return h.next.Handle(ctx, r)
+	}
+
+	key := h.recordKey(r)
+	if entry, ok := s.entries[key]; ok {
+		entry.count++
+		entry.ctx, entry.record = context.WithoutCancel(ctx), r.Clone()
+		s.mu.Unlock()
+		return nil
+	}
+
+	s.entries[key] = &dedupEntry{
+		handler: h.next,
+		timer:   time.AfterFunc(s.config.Window, func() { _ = s.emit(key) }), //nolint: errcheck
+	}
+	s.mu.Unlock()
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a new DedupHandler sharing the state whose next handler has the given attributes.
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &DedupHandler{next: h.next.WithAttrs(attrs), key: h.key + attrsKey(attrs), state: h.state}
+}
+
+// WithGroup returns a new DedupHandler sharing the state whose next handler has the given group.
+func (h *DedupHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &DedupHandler{next: h.next.WithGroup(name), key: h.key + name + ".", state: h.state}
+}
+
+// Flush writes the collapsed records for all windows that have not ended.
+func (h *DedupHandler) Flush() error {
+	h.state.mu.Lock()
+	keys := make([]string, 0, len(h.state.entries))
+	for key := range h.state.entries {
+		keys = append(keys, key)
+	}
+	h.state.mu.Unlock()
+
+	var err error
+	for _, key := range keys {
+		if e := h.state.emit(key); e != nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Dropped returns the number of records discarded by sampling and rate limits.
+func (h *DedupHandler) Dropped() uint64 {
+	return h.state.dropped.Load()
+}
+
+// recordKey returns the key identifying records with the same level, message and attributes.
+func (h *DedupHandler) recordKey(r slog.Record) string {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return r.Level.String() + "|" + r.Message + "|" + h.key + attrsKey(attrs)
+}
+
+// allow reports whether a record with the message and level is within the sampling and rate limits for the current
+// interval. Rate limits apply at all levels, sampling only applies to WARN and ERROR records if SampleAll is set.
+func (s *dedupState) allow(msg string, level slog.Level) bool {
+	limit, limited := s.config.RateLimits[msg]
+	sampled := s.config.First > 0 && (s.config.SampleAll || level < slog.LevelWarn)
+	if !limited && !sampled {
+		return true
+	}
+
+	now := time.Now()
+	s.sweep(now)
+	counter, ok := s.counters[msg]
+	if !ok || now.Sub(counter.start) >= s.config.Interval {
+		counter = &dedupCounter{start: now}
+		s.counters[msg] = counter
+	}
+	counter.count++
+
+	if limited {
+		return counter.count <= limit
+	}
+	if counter.count <= s.config.First {
+		return true
+	}
+	return s.config.Thereafter > 0 && (counter.count-s.config.First)%s.config.Thereafter == 0
+}
+
+// sweep removes the counters for intervals that have ended, at most once each interval, so that the number of
+// counters is limited to the messages logged in recent intervals.
+func (s *dedupState) sweep(now time.Time) {
+	if now.Sub(s.swept) < s.config.Interval {
+		return
+	}
+	s.swept = now
+	for msg, counter := range s.counters {
+		if now.Sub(counter.start) >= s.config.Interval {
+			delete(s.counters, msg)
+		}
+	}
+}
+
+// emit ends the window for a key, writing the last repeated record with the number of repeats.
+func (s *dedupState) emit(key string) error {
+	s.mu.Lock()
+	entry, ok := s.entries[key]
+	if ok {
+		delete(s.entries, key)
+		entry.timer.Stop()
+	}
+	s.mu.Unlock()
+
+	if !ok || entry.count == 0 {
+		return nil
+	}
+	record := entry.record.Clone()
+	record.AddAttrs(slog.Int(RepeatedKey, entry.count))
+	return entry.handler.Handle(entry.ctx, record)
+}
+
+// attrsKey returns a string identifying a list of attributes.
+func attrsKey(attrs []slog.Attr) string {
+	var b strings.Builder
+	for _, a := range attrs {
+		b.WriteString(a.Key)
+		b.WriteByte('=')
+		b.WriteString(a.Value.Resolve().String())
+		b.WriteByte(';')
+	}
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DumpValueKey is the attribute key used for the value logged by Dump.
+	DumpValueKey = "value"
+	// DumpSizeKey is the attribute key used for the size in bytes of the value logged by Dump.
+	DumpSizeKey = "size"
+	// DumpTruncatedKey is the attribute key set to true when the value logged by Dump has been truncated.
+	DumpTruncatedKey = "truncated"
+
+	// DefaultDumpLimit is the size in bytes above which values logged by Dump are truncated unless changed using SetDumpLimit.
+	DefaultDumpLimit = 16 * 1024
+)
+
+var dumpLimit atomic.Int64 //nolint: gochecknoglobals
+
+func init() {
+	dumpLimit.Store(DefaultDumpLimit)
+}
+
+// SetDumpLimit sets the size in bytes above which values logged by Dump are truncated, zero or less disables truncation.
+func SetDumpLimit(limit int) {
+	dumpLimit.Store(int64(limit))
+}
+
+// Dump logs a value, such as a request body or API response, at the level specified to the package log output with the
+// label as the message. See LogDump.
+func Dump(ctx context.Context, level slog.Level, label string, value any) {
+	dump(ctx, internalLogger(), level, label, value)
+}
+
+// LogDump logs a value at the level specified using the logger specified with the source set to the caller. Values that
+// are JSON, or can be marshalled to JSON, are logged as structured data with sensitive fields redacted, other text is
+// logged as a redacted string. Values larger than the dump limit are logged as a truncated string. Nothing is done,
+// including marshalling the value, unless the level is enabled for the caller's package.
+func LogDump(ctx context.Context, log *slog.Logger, level slog.Level, label string, value any) {
+	if log == nil {
+		log = internalLogger()
+	}
+	dump(ctx, log, level, label, value)
+}
+
+// dump logs the value with the source set to the caller of Dump or LogDump.
+func dump(ctx context.Context, log *slog.Logger, level slog.Level, label string, value any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !log.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) //nolint: mnd // skip runtime.Callers, dump and Dump or LogDump.
+	if pkgLevels.Load() != nil && level < LevelFor(packageOfPC(pcs[0])) {
+		return
+	}
+
+	record := slog.NewRecord(time.Now(), level, label, pcs[0])
+	record.AddAttrs(dumpAttrs(value)...)
+	_ = log.Handler().Handle(ctx, record) //nolint: errcheck
+}
+
+// dumpAttrs returns the attributes describing a value, redacted and truncated if required.
+func dumpAttrs(value any) []slog.Attr {
+	r := GetRedactor()
+	data, isJSON := dumpJSON(value)
+	if isJSON {
+		if redacted, err := r.RedactJSON(data); err == nil {
+			data = redacted
+		}
+	} else {
+		data = []byte(r.RedactString(string(data)))
+	}
+
+	attrs := []slog.Attr{slog.Int(DumpSizeKey, len(data))}
+	if limit := int(dumpLimit.Load()); limit > 0 && len(data) > limit {
+		for limit > 0 && !utf8.RuneStart(data[limit]) {
+			limit--
+		}
+		return append(attrs, slog.String(DumpValueKey, string(data[:limit])), slog.Bool(DumpTruncatedKey, true))
+	}
+	if !isJSON {
+		return append(attrs, slog.String(DumpValueKey, string(data)))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return append(attrs, slog.String(DumpValueKey, string(data)))
+	}
+	return append(attrs, slog.Any(DumpValueKey, decoded))
+}
+
+// dumpJSON returns a value as JSON, reporting false if it is text that is not valid JSON or cannot be marshalled.
+func dumpJSON(value any) ([]byte, bool) {
+	var data []byte
+	switch v := value.(type) {
+	case string:
+		data = []byte(v)
+	case *string:
+		if v != nil {
+			data = []byte(*v)
+		}
+	case []byte:
+		data = v
+	case json.RawMessage:
+		data = v
+	default:
+		marshalled, err := json.Marshal(value)
+		if err != nil {
+			return []byte(err.Error()), false
+		}
+		return marshalled, true
+	}
+	trimmed := strings.TrimSpace(string(data))
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(data) {
+		return data, true
+	}
+	return data, false
+}
@@ -0,0 +1,122 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	// ErrorMsgKey is the attribute key used for the message of an Error when it is logged.
+	ErrorMsgKey = "msg"
+	// ErrorCallerKey is the attribute key used for the location an Error was created when it is logged.
+	ErrorCallerKey = "caller"
+	// ErrorCauseKey is the attribute key used for the error wrapped by an Error when it is logged.
+	ErrorCauseKey = "cause"
+	// ErrorStackKey is the attribute key used for the stack captured by an Error when it is logged.
+	ErrorStackKey = "stack"
+)
+
+// Error is an error that records where it was created, attributes describing it and optionally the stack. It can wrap
+// another error for use with errors.Is and errors.As. When logged it is emitted as a group of its attributes.
+type Error struct {
+	msg   string
+	cause error
+	attrs []slog.Attr
+	pc    uintptr
+	stack []uintptr
+}
+
+// NewError returns an Error with the message and attributes, which are key/value pairs and/or slog.Attr values.
+func NewError(msg string, args ...any) *Error {
+	return newError(nil, msg, args)
+}
+
+// WrapError returns an Error with the message and attributes that wraps an error.
+func WrapError(err error, msg string, args ...any) *Error {
+	return newError(err, msg, args)
+}
+
+// newError returns an Error recording the caller of NewError or WrapError.
+func newError(err error, msg string, args []any) *Error {
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) //nolint: mnd // skip runtime.Callers, newError and NewError or WrapError.
+	return &Error{msg: msg, cause: err, attrs: argsToAttrs(args), pc: pcs[0]}
+}
+
+// With adds attributes to the error and returns it.
+func (e *Error) With(args ...any) *Error {
+	e.attrs = append(e.attrs, argsToAttrs(args)...)
+	return e
+}
+
+// WithStack records the stack of the caller and returns the error.
+func (e *Error) WithStack() *Error {
+	pcs := make([]uintptr, stackDepth)
+	n := runtime.Callers(2, pcs) //nolint: mnd // skip runtime.Callers and WithStack.
+	e.stack = pcs[:n]
+	return e
+}
+
+// Error returns the message followed by the message of the wrapped error, if any.
+func (e *Error) Error() string {
+	if e.cause == nil {
+		return e.msg
+	}
+	return fmt.Sprintf("%s: %s", e.msg, e.cause)
+}
+
+// Unwrap returns the wrapped error.
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Attrs returns the attributes of the error.
+func (e *Error) Attrs() []slog.Attr {
+	return e.attrs
+}
+
+// Caller returns the location where the error was created.
+func (e *Error) Caller() slog.Source {
+	frame, _ := runtime.CallersFrames([]uintptr{e.pc}).Next()
+	return slog.Source{Function: filepath.Base(frame.Function), File: filepath.Base(frame.File), Line: frame.Line}
+}
+
+// Stack returns the stack recorded by WithStack, if any.
+func (e *Error) Stack() []slog.Source {
+	stack := []slog.Source{}
+	frames := runtime.CallersFrames(e.stack)
+	for len(e.stack) > 0 {
+		frame, more := frames.Next()
+		stack = append(stack, slog.Source{Function: filepath.Base(frame.Function), File: filepath.Base(frame.File), Line: frame.Line})
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// LogValue returns a group containing the message, attributes, caller, wrapped error and stack.
+func (e *Error) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(e.attrs)+4) //nolint: mnd
+	attrs = append(attrs, slog.String(ErrorMsgKey, e.msg))
+	attrs = append(attrs, e.attrs...)
+	attrs = append(attrs, slog.String(ErrorCallerKey, sourceText(e.Caller())))
+	if e.cause != nil {
+		attrs = append(attrs, slog.Any(ErrorCauseKey, e.cause))
+	}
+	if len(e.stack) > 0 {
+		stack := []string{}
+		for _, source := range e.Stack() {
+			stack = append(stack, sourceText(source))
+		}
+		attrs = append(attrs, slog.Any(ErrorStackKey, stack))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// sourceText returns the source file, line and function in the same format as CallerText.
+func sourceText(source slog.Source) string {
+	return fmt.Sprintf("%s(%d) %s", source.File, source.Line, source.Function)
+}
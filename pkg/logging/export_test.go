@@ -2,4 +2,18 @@ package logging
 
 var (
 	SetSourceName = setSourceName
+	// SetRotatingFileStart sets the time a log file written by a RotatingFile was first written to.
+	SetRotatingFileStart = writeStart
 )
+
+// DedupCounters returns the number of messages a dedup handler is counting for sampling and rate limits.
+func DedupCounters(h *DedupHandler) int {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	return len(h.state.counters)
+}
+
+// SetConsoleColor enables or disables colour output for a console handler regardless of its writer.
+func SetConsoleColor(h *ConsoleHandler, on bool) {
+	h.color = on
+}
@@ -0,0 +1,140 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultFatalExitCode is the exit code used by Fatal unless changed using SetFatalExitCode.
+const defaultFatalExitCode = 1
+
+// ErrFatal is wrapped by the value Fatal panics with when called in a test binary or if the exit function set using
+// SetExitFunc returns, tests can recover it and use errors.Is to check a fatal error was logged.
+var ErrFatal = errors.New("fatal error")
+
+// FatalHook is called by Fatal before the process exits, e.g. to flush buffered handlers, send a notification or write
+// a crash file. The record holds the fatal message and attributes.
+type FatalHook func(ctx context.Context, record slog.Record)
+
+var (
+	fatalHooks    []*FatalHook //nolint: gochecknoglobals
+	fatalHooksMu  sync.Mutex   //nolint: gochecknoglobals
+	fatalExitCode atomic.Int32 //nolint: gochecknoglobals
+
+	exitFunc atomic.Pointer[func(int)] //nolint: gochecknoglobals
+)
+
+func init() {
+	fatalExitCode.Store(defaultFatalExitCode)
+	exit := exitProcess
+	exitFunc.Store(&exit)
+}
+
+// exitProcess is the default exit function, it exits the process unless running in a test binary, detected by the
+// flags registered by the testing package, in which case it returns so that Fatal panics.
+func exitProcess(code int) {
+	if flag.Lookup("test.v") != nil {
+		return
+	}
+	os.Exit(code)
+}
+
+// SetExitFunc sets the function called by Fatal to exit the process, e.g. so a test can check the exit code. If the
+// function returns Fatal panics with an error wrapping ErrFatal. It returns a function that restores the previous exit
+// function.
+func SetExitFunc(exit func(code int)) func() {
+	previous := exitFunc.Swap(&exit)
+	return func() {
+		exitFunc.Store(previous)
+	}
+}
+
+// OnFatal registers a hook to be called by Fatal, hooks are called in the order they were registered.
+// It returns a function that removes the hook.
+func OnFatal(hook FatalHook) func() {
+	fatalHooksMu.Lock()
+	defer fatalHooksMu.Unlock()
+
+	entry := &hook
+	fatalHooks = append(fatalHooks, entry)
+	return func() {
+		fatalHooksMu.Lock()
+		defer fatalHooksMu.Unlock()
+		for i, h := range fatalHooks {
+			if h == entry {
+				fatalHooks = append(fatalHooks[:i:i], fatalHooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// SetFatalExitCode sets the exit code used by Fatal.
+func SetFatalExitCode(code int) {
+	fatalExitCode.Store(int32(code)) //nolint: gosec
+}
+
+// Fatal logs a message at FATAL level to the package log output, runs the hooks registered using OnFatal and exits
+// using the function set by SetExitFunc, os.Exit by default. When called in a test binary it panics with an error
+// wrapping ErrFatal instead of exiting unless an exit function has been set.
+func Fatal(ctx context.Context, msg string, args ...any) {
+	fatal(ctx, internalLogger(), 0, msg, args)
+}
+
+// LogFatal is the same as Fatal but logs the message using the logger specified.
+func LogFatal(ctx context.Context, log *slog.Logger, msg string, args ...any) {
+	LogFatalDepth(ctx, log, 1, msg, args...)
+}
+
+// LogFatalDepth is the same as LogFatal but sets the source of the record to a caller further up the stack, depth is
+// the number of callers to skip, e.g. 1 when called by a helper function so the source is the helper's caller.
+func LogFatalDepth(ctx context.Context, log *slog.Logger, depth int, msg string, args ...any) {
+	if log == nil {
+		log = internalLogger()
+	}
+	fatal(ctx, log, depth, msg, args)
+}
+
+// fatal logs the message with the source set to the caller of Fatal or LogFatalDepth, skipping the number of callers
+// specified, runs the hooks and exits.
+func fatal(ctx context.Context, log *slog.Logger, skip int, msg string, args []any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(3+skip, pcs[:]) //nolint: mnd // skip runtime.Callers, fatal and Fatal or LogFatalDepth.
+	record := slog.NewRecord(time.Now(), LevelFatal, msg, pcs[0])
+	record.Add(args...)
+	if log.Enabled(ctx, LevelFatal) {
+		_ = log.Handler().Handle(ctx, record.Clone()) //nolint: errcheck
+	}
+
+	fatalHooksMu.Lock()
+	hooks := append([]*FatalHook{}, fatalHooks...)
+	fatalHooksMu.Unlock()
+	for _, hook := range hooks {
+		runFatalHook(ctx, *hook, record)
+	}
+
+	(*exitFunc.Load())(int(fatalExitCode.Load()))
+	panic(fmt.Errorf("%w: %s", ErrFatal, msg))
+}
+
+// runFatalHook calls a hook, recovering from any panic so the remaining hooks are called and the process exits.
+func runFatalHook(ctx context.Context, hook FatalHook, record slog.Record) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "fatal hook panicked: %v\n", r)
+		}
+	}()
+	hook(ctx, record.Clone())
+}
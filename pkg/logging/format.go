@@ -0,0 +1,299 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Format is a log output format.
+type Format string
+
+const (
+	// FormatJSON selects JSON output.
+	FormatJSON Format = "json"
+	// FormatText selects the slog text output.
+	FormatText Format = "text"
+	// FormatLogfmt selects logfmt output using ts, level, caller and msg keys.
+	FormatLogfmt Format = "logfmt"
+	// FormatECS selects Elastic Common Schema JSON output using @timestamp, log.level, log.origin and message keys.
+	FormatECS Format = "ecs"
+	// FormatConsole selects human readable output, coloured by level when writing to a terminal.
+	FormatConsole Format = "console"
+	// FormatGCP selects Google Cloud Logging structured JSON output using severity, sourceLocation and message keys.
+	FormatGCP Format = "gcp"
+
+	// logFormatEnvVar is the environmental variable name used to set the log format, defaults to json if not set.
+	logFormatEnvVar = "LOG_FORMAT"
+
+	logfmtTimeKey   = "ts"
+	logfmtSourceKey = "caller"
+
+	ecsVersion      = "8.11.0"
+	ecsVersionKey   = "ecs.version"
+	ecsTimeKey      = "@timestamp"
+	ecsLevelKey     = "log.level"
+	ecsSourceKey    = "log.origin"
+	ecsMessageKey   = "message"
+	gcpLevelKey     = "severity"
+	gcpSourceKey    = "logging.googleapis.com/sourceLocation"
+	gcpMessageKey   = "message"
+	gcpCritical     = "CRITICAL"
+	gcpWarning      = "WARNING"
+	gcpDefaultLevel = "DEFAULT"
+)
+
+var (
+	errInvalidFormat = errors.New("invalid log format")
+
+	// logFormat holds the Format used by loggers created by this package, except text loggers.
+	logFormat atomic.Value //nolint: gochecknoglobals
+)
+
+// setLogFormat returns the log format selected by the user.
+func setLogFormat() Format {
+	name, ok := os.LookupEnv(logFormatEnvVar)
+	if !ok {
+		return FormatJSON
+	}
+	format, err := ParseFormat(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid log format: %s, defaulting to json\n", name)
+		return FormatJSON
+	}
+	return format
+}
+
+// GetLogFormat returns the format used by loggers created by this package.
+func GetLogFormat() Format {
+	return logFormat.Load().(Format) //nolint: forcetypeassert
+}
+
+// SetLogFormat sets the format used by loggers created after it is called, text loggers always use the text format.
+// Loggers returned by TraceLogger, including TraceLog, use the new format for records logged after it is called.
+func SetLogFormat(format Format) error {
+	format, err := ParseFormat(string(format))
+	if err != nil {
+		return err
+	}
+	logFormat.Store(format)
+	return nil
+}
+
+// currentFormatHandler is a slog.Handler that writes each record using the log format set when it is handled.
+type currentFormatHandler struct {
+	out       io.Writer
+	addSource bool
+	derive    func(slog.Handler) slog.Handler // Adds the attributes and groups of the handler to a format handler.
+	handlers  *sync.Map                       // The format handlers created, keyed by format.
+}
+
+// newCurrentFormatHandler returns a handler writing to the writer using the current log format.
+func newCurrentFormatHandler(out io.Writer, addSource bool) *currentFormatHandler {
+	return &currentFormatHandler{
+		out:       out,
+		addSource: addSource,
+		derive:    func(handler slog.Handler) slog.Handler { return handler },
+		handlers:  &sync.Map{},
+	}
+}
+
+// handler returns the handler for the current log format, creating it if required.
+func (h *currentFormatHandler) handler() slog.Handler {
+	format := GetLogFormat()
+	handler, ok := h.handlers.Load(format)
+	if !ok {
+		handler, _ = h.handlers.LoadOrStore(format, h.derive(formatHandler(h.out, format, h.addSource)))
+	}
+	return handler.(slog.Handler) //nolint: forcetypeassert
+}
+
+// Enabled reports whether the handler for the current format handles records at the given level.
+func (h *currentFormatHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler().Enabled(ctx, level)
+}
+
+// Handle writes the record using the handler for the current format.
+func (h *currentFormatHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler().Handle(ctx, r)
+}
+
+// WithAttrs returns a new currentFormatHandler whose format handlers have the given attributes.
+func (h *currentFormatHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	derive := h.derive
+	return &currentFormatHandler{
+		out:       h.out,
+		addSource: h.addSource,
+		derive:    func(handler slog.Handler) slog.Handler { return derive(handler).WithAttrs(attrs) },
+		handlers:  &sync.Map{},
+	}
+}
+
+// WithGroup returns a new currentFormatHandler whose format handlers have the given group.
+func (h *currentFormatHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	derive := h.derive
+	return &currentFormatHandler{
+		out:       h.out,
+		addSource: h.addSource,
+		derive:    func(handler slog.Handler) slog.Handler { return derive(handler).WithGroup(name) },
+		handlers:  &sync.Map{},
+	}
+}
+
+// ParseFormat returns the log format for a format name.
+func ParseFormat(name string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(name)))
+	switch format {
+	case FormatJSON, FormatText, FormatLogfmt, FormatECS, FormatGCP, FormatConsole:
+		return format, nil
+	default:
+		return FormatJSON, fmt.Errorf("%w: %s", errInvalidFormat, name)
+	}
+}
+
+// newFormatHandler returns a handler writing records to the writer in the format specified.
+func newFormatHandler(out io.Writer, format Format, level slog.Leveler, addSource bool) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{
+		Level:     level,
+		AddSource: addSource,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr { //nolint: revive
+			a = setLogLevelName(a)
+			a = setSourceName(a)
+			return a
+		},
+	}
+
+	switch format {
+	case FormatJSON, "":
+		opts.ReplaceAttr = withoutHighlight(opts.ReplaceAttr)
+		return slog.NewJSONHandler(out, opts), nil
+	case FormatText:
+		opts.ReplaceAttr = withoutHighlight(opts.ReplaceAttr)
+		return slog.NewTextHandler(out, opts), nil
+	case FormatLogfmt:
+		opts.ReplaceAttr = withoutHighlight(logfmtAttr)
+		return slog.NewTextHandler(out, opts), nil
+	case FormatECS:
+		opts.ReplaceAttr = withoutHighlight(ecsAttr)
+		return slog.NewJSONHandler(out, opts).WithAttrs([]slog.Attr{slog.String(ecsVersionKey, ecsVersion)}), nil
+	case FormatGCP:
+		opts.ReplaceAttr = withoutHighlight(gcpAttr)
+		return slog.NewJSONHandler(out, opts), nil
+	case FormatConsole:
+		return NewConsoleHandler(out, opts), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errInvalidFormat, format)
+	}
+}
+
+// withoutHighlight returns a ReplaceAttr function that removes the highlight attribute, which is only used by the
+// console handler, before calling replace.
+func withoutHighlight(replace func(groups []string, a slog.Attr) slog.Attr) func(groups []string, a slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 && a.Key == HighlightKey && a.Value.Kind() == slog.KindBool {
+			return slog.Attr{}
+		}
+		return replace(groups, a)
+	}
+}
+
+// logfmtAttr renames and formats the built in attributes for logfmt output.
+func logfmtAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.TimeKey:
+		a.Key = logfmtTimeKey
+		a.Value = slog.StringValue(a.Value.Time().UTC().Format(time.RFC3339Nano))
+	case slog.LevelKey:
+		a = setLogLevelName(a)
+		a.Value = slog.StringValue(strings.ToLower(a.Value.String()))
+	case slog.SourceKey:
+		a = setSourceName(a)
+		if source, ok := a.Value.Any().(*slog.Source); ok {
+			a.Key = logfmtSourceKey
+			a.Value = slog.StringValue(fmt.Sprintf("%s:%d", source.File, source.Line))
+		}
+	}
+	return a
+}
+
+// ecsAttr renames and formats the built in attributes for Elastic Common Schema output.
+func ecsAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.TimeKey:
+		a.Key = ecsTimeKey
+		a.Value = slog.StringValue(a.Value.Time().UTC().Format(time.RFC3339Nano))
+	case slog.LevelKey:
+		a = setLogLevelName(a)
+		a.Key = ecsLevelKey
+		a.Value = slog.StringValue(strings.ToLower(a.Value.String()))
+	case slog.MessageKey:
+		a.Key = ecsMessageKey
+	case slog.SourceKey:
+		a = setSourceName(a)
+		if source, ok := a.Value.Any().(*slog.Source); ok {
+			a = slog.Group(ecsSourceKey,
+				slog.Group("file", slog.String("name", source.File), slog.Int("line", source.Line)),
+				slog.String("function", source.Function))
+		}
+	}
+	return a
+}
+
+// gcpAttr renames and formats the built in attributes for Google Cloud Logging structured output.
+func gcpAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.LevelKey:
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(gcpSeverity(level))
+		}
+		a.Key = gcpLevelKey
+	case slog.MessageKey:
+		a.Key = gcpMessageKey
+	case slog.SourceKey:
+		a = setSourceName(a)
+		if source, ok := a.Value.Any().(*slog.Source); ok {
+			a = slog.Group(gcpSourceKey,
+				slog.String("file", source.File), slog.String("line", strconv.Itoa(source.Line)),
+				slog.String("function", source.Function))
+		}
+	}
+	return a
+}
+
+// gcpSeverity returns the Google Cloud Logging severity for a level, TRACE maps to DEBUG and FATAL to CRITICAL.
+func gcpSeverity(level slog.Level) string {
+	switch {
+	case level >= LevelFatal:
+		return gcpCritical
+	case level >= slog.LevelError:
+		return LevelName(slog.LevelError)
+	case level >= slog.LevelWarn:
+		return gcpWarning
+	case level >= slog.LevelInfo:
+		return LevelName(slog.LevelInfo)
+	case level >= LevelTrace:
+		return LevelName(slog.LevelDebug)
+	default:
+		return gcpDefaultLevel
+	}
+}
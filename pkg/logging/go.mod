@@ -3,6 +3,10 @@ module github.com/paul-carlton/goutils/pkg/logging
 go 1.23.2
 
 require (
+	github.com/fatih/color v1.18.0
+	github.com/go-logr/logr v1.4.2
+	github.com/mattn/go-isatty v0.0.20
+	github.com/paul-carlton/goutils/pkg/miscutils v1.0.0
 	github.com/paul-carlton/goutils/pkg/testutils v1.0.0
 	k8s.io/apimachinery v0.31.2
 )
@@ -10,15 +14,16 @@ require (
 require (
 	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
 	github.com/fxamacker/cbor/v2 v2.7.0 // indirect
-	github.com/go-logr/logr v1.4.2 // indirect
 	github.com/gogo/protobuf v1.3.2 // indirect
 	github.com/google/gofuzz v1.2.0 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/kylelemons/godebug v1.1.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/x448/float16 v0.8.4 // indirect
 	golang.org/x/net v0.26.0 // indirect
+	golang.org/x/sys v0.25.0 // indirect
 	golang.org/x/text v0.16.0 // indirect
 	gopkg.in/inf.v0 v0.9.1 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
@@ -0,0 +1,198 @@
+package logging
+
+import (
+	"encoding/json"
+	"log/slog"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	readyCondition = "Ready"
+	secretKind     = "Secret"
+)
+
+// k8sObject is a slog.LogValuer for a Kubernetes object.
+type k8sObject struct {
+	obj k8sruntime.Object
+}
+
+// k8sDiff is a slog.LogValuer for the changes between two versions of a Kubernetes object.
+type k8sDiff struct {
+	oldObj, newObj k8sruntime.Object
+}
+
+// K8sObject returns a value that logs a Kubernetes object as a group containing its kind, namespace, name, uid,
+// resource version, generation and the status and reason of its Ready condition if it has one.
+func K8sObject(obj k8sruntime.Object) slog.LogValuer {
+	return k8sObject{obj: obj}
+}
+
+// K8sDiff returns a value that logs the JSON merge patch that changes the old version of a Kubernetes object into the
+// new version. The resource version and managed fields are ignored. Secret data is compared before it is redacted so
+// changed values are included in the patch, as [REDACTED].
+func K8sDiff(oldObj, newObj k8sruntime.Object) slog.LogValuer {
+	return k8sDiff{oldObj: oldObj, newObj: newObj}
+}
+
+// LogValue returns a group describing the object.
+func (o k8sObject) LogValue() slog.Value {
+	if isNilObject(o.obj) {
+		return slog.StringValue("nil")
+	}
+
+	attrs := []slog.Attr{}
+	if gvk := o.obj.GetObjectKind().GroupVersionKind(); len(gvk.Kind) > 0 {
+		attrs = append(attrs, slog.String("kind", gvk.Kind), slog.String("apiVersion", gvk.GroupVersion().String()))
+	}
+
+	mobj, ok := o.obj.(metav1.Object)
+	if !ok {
+		return slog.GroupValue(attrs...)
+	}
+	if len(mobj.GetNamespace()) > 0 {
+		attrs = append(attrs, slog.String("namespace", mobj.GetNamespace()))
+	}
+	attrs = append(attrs,
+		slog.String("name", mobj.GetName()),
+		slog.String("uid", string(mobj.GetUID())),
+		slog.String("resourceVersion", mobj.GetResourceVersion()),
+		slog.Int64("generation", mobj.GetGeneration()))
+
+	if status, reason, found := readyStatus(o.obj); found {
+		attrs = append(attrs, slog.String("ready", status))
+		if len(reason) > 0 {
+			attrs = append(attrs, slog.String("reason", reason))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue returns the merge patch as a compact JSON string.
+func (d k8sDiff) LogValue() slog.Value {
+	oldObj, err := objectMap(d.oldObj)
+	if err != nil {
+		return slog.StringValue(err.Error())
+	}
+	newObj, err := objectMap(d.newObj)
+	if err != nil {
+		return slog.StringValue(err.Error())
+	}
+
+	diff := mergePatch(oldObj, newObj)
+	if isSecret(d.oldObj) || isSecret(d.newObj) {
+		redactSecretData(diff)
+	}
+	patch, err := json.Marshal(diff)
+	if err != nil {
+		return slog.StringValue(err.Error())
+	}
+	patch, err = GetRedactor().RedactJSON(patch)
+	if err != nil {
+		return slog.StringValue(err.Error())
+	}
+	return slog.StringValue(string(patch))
+}
+
+// isNilObject reports whether an object is nil or a nil pointer.
+func isNilObject(obj k8sruntime.Object) bool {
+	if obj == nil {
+		return true
+	}
+	value := reflect.ValueOf(obj)
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
+
+// readyStatus returns the status and reason of the Ready condition of an object, if it has one.
+func readyStatus(obj k8sruntime.Object) (string, string, bool) {
+	content, err := k8sruntime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", "", false
+	}
+	status, _ := content["status"].(map[string]interface{}) //nolint: errcheck
+	conditions, _ := status["conditions"].([]interface{})   //nolint: errcheck
+	for _, item := range conditions {
+		condition, _ := item.(map[string]interface{}) //nolint: errcheck
+		if condition["type"] == readyCondition {
+			conditionStatus, _ := condition["status"].(string) //nolint: errcheck
+			reason, _ := condition["reason"].(string)          //nolint: errcheck
+			return conditionStatus, reason, true
+		}
+	}
+	return "", "", false
+}
+
+// objectMap returns an object as a map, without the fields that change on every update.
+func objectMap(obj k8sruntime.Object) (map[string]interface{}, error) {
+	if isNilObject(obj) {
+		return map[string]interface{}{}, nil
+	}
+	content, err := k8sruntime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "resourceVersion")
+		delete(metadata, "managedFields")
+	}
+	return content, nil
+}
+
+// isSecret reports whether an object is a Secret.
+func isSecret(obj k8sruntime.Object) bool {
+	if isNilObject(obj) {
+		return false
+	}
+	return obj.GetObjectKind().GroupVersionKind().Kind == secretKind ||
+		reflect.Indirect(reflect.ValueOf(obj)).Type().Name() == secretKind
+}
+
+// redactSecretData replaces the values of Secret data keys that are added or changed in a patch, keys that are removed
+// are left as null.
+func redactSecretData(patch map[string]interface{}) {
+	for _, field := range []string{"data", "stringData"} {
+		data, ok := patch[field].(map[string]interface{})
+		if !ok {
+			if patch[field] != nil {
+				patch[field] = RedactedValue
+			}
+			continue
+		}
+		for key, value := range data {
+			if value != nil {
+				data[key] = RedactedValue
+			}
+		}
+	}
+}
+
+// mergePatch returns the JSON merge patch, as defined by RFC 7386, that changes the old value into the new value.
+func mergePatch(oldObj, newObj map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for key, oldValue := range oldObj {
+		newValue, ok := newObj[key]
+		if !ok {
+			patch[key] = nil
+			continue
+		}
+		oldMap, oldIsMap := oldValue.(map[string]interface{})
+		newMap, newIsMap := newValue.(map[string]interface{})
+		if oldIsMap && newIsMap {
+			if child := mergePatch(oldMap, newMap); len(child) > 0 {
+				patch[key] = child
+			}
+			continue
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			patch[key] = newValue
+		}
+	}
+	for key, newValue := range newObj {
+		if _, ok := oldObj[key]; !ok {
+			patch[key] = newValue
+		}
+	}
+	return patch
+}
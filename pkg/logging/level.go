@@ -0,0 +1,165 @@
+package logging
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultLevelFileInterval is the default interval at which WatchLevelFile checks the env file for changes.
+	DefaultLevelFileInterval = time.Second * 10
+
+	maxLevelRequestSize = 1024
+)
+
+var (
+	errInvalidLevel = errors.New("invalid logging level")
+
+	// levelCycle defines the order levels are stepped through by CycleLogLevel.
+	levelCycle = []slog.Level{slog.LevelInfo, slog.LevelDebug, LevelTrace} //nolint: gochecknoglobals
+)
+
+// ParseLevel returns the logging level for a level name, e.g. TRACE, DEBUG, INFO, WARN, ERROR or FATAL.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "TRACE":
+		return LevelTrace, nil
+	case "DEBUG":
+		return slog.LevelDebug, nil
+	case "INFO":
+		return slog.LevelInfo, nil
+	case "WARN":
+		return slog.LevelWarn, nil
+	case "ERROR":
+		return slog.LevelError, nil
+	case "FATAL":
+		return LevelFatal, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("%w: %s", errInvalidLevel, name)
+	}
+}
+
+// LevelName returns the name of a logging level, including the custom levels added by this package.
+func LevelName(level slog.Level) string {
+	if name, ok := levelNames[level]; ok {
+		return name
+	}
+	return level.String()
+}
+
+// SetLevel sets the logging level used by all loggers created by this package.
+func SetLevel(level slog.Level) {
+	previous := LogLevel.Level()
+	LogLevel.Set(level)
+	if previous != level {
+		internalLogger().Info("log level changed", "from", LevelName(previous), "to", LevelName(level))
+	}
+}
+
+// CycleLogLevel steps the logging level through INFO, DEBUG and TRACE, returning the new level.
+// If the current level is not one of these the level is set to INFO.
+func CycleLogLevel() slog.Level {
+	next := levelCycle[0]
+	current := LogLevel.Level()
+	for i, level := range levelCycle {
+		if level == current {
+			next = levelCycle[(i+1)%len(levelCycle)]
+			break
+		}
+	}
+	SetLevel(next)
+	return next
+}
+
+// LevelHandler returns an http.Handler that can be added to an admin endpoint to view and change the logging level.
+// A GET request returns the current level specification, a PUT or POST request sets the levels using the specification
+// in the 'level' query or form parameter or, if that is not present, the request body, e.g. "INFO,httpclient=TRACE".
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			name := r.FormValue("level")
+			if len(name) == 0 {
+				body, err := io.ReadAll(io.LimitReader(r.Body, maxLevelRequestSize))
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				name = string(body)
+			}
+			if err := SetLevelSpec(name); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodPut, http.MethodPost}, ", "))
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		fmt.Fprintln(w, GetLevelSpec())
+	})
+}
+
+// WatchLevelFile polls an env file, e.g. a mounted ConfigMap, and applies the LOG_LEVEL setting it contains
+// whenever the file changes. It returns immediately, watching continues until the context is cancelled.
+func WatchLevelFile(ctx context.Context, path string, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultLevelFileInterval
+	}
+
+	go func() {
+		var lastMod time.Time
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if info, err := os.Stat(path); err == nil && !info.ModTime().Equal(lastMod) {
+				lastMod = info.ModTime()
+				if err := applyLevelFile(path); err != nil {
+					internalLogger().Warn("failed to apply log level file", "file", path, "error", err)
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// applyLevelFile reads an env file and sets the logging levels from its LOG_LEVEL entry, if present.
+func applyLevelFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "export ")
+		name, value, found := strings.Cut(line, "=")
+		if !found || strings.TrimSpace(name) != logLevelEnvVar {
+			continue
+		}
+		return SetLevelSpec(strings.Trim(strings.TrimSpace(value), `"'`))
+	}
+	return scanner.Err()
+}
+
+// internalLogger returns a logger for messages generated by this package.
+func internalLogger() *slog.Logger {
+	if LogOut != nil {
+		return NewLoggerTo(LogOut)
+	}
+	return NewLoggerTo(os.Stderr)
+}
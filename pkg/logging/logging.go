@@ -14,6 +14,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
@@ -29,8 +30,6 @@ const (
 	// MyCallersCallersCaller is the setting for the function that called the function that called the function that called the function calling MyCaller.
 	MyCallersCallersCaller = 6
 
-	eleven = 11
-
 	stackDepth = 32
 
 	// logLevelEnvVar is the environmental variable name used to set the log level, defaults to INFO if not set.
@@ -58,53 +57,47 @@ var (
 	}
 
 	sourcePathDepth int //nolint: gochecknoglobals
-	// LogLevel contains the logging level set.
-	LogLevel  slog.Level //nolint: gochecknoglobals
-	LogOut    io.Writer  //nolint: gochecknoglobals
-	logSource bool       //nolint: gochecknoglobals
+	// LogLevel contains the logging level set, it is shared by all loggers created by this package
+	// so changing it at runtime affects existing loggers as well as those created afterwards.
+	LogLevel  = new(slog.LevelVar) //nolint: gochecknoglobals
+	LogOut    io.Writer            //nolint: gochecknoglobals
+	logSource bool                 //nolint: gochecknoglobals
 
-	// traceLog is used by trace logging functions that replace the source information with the callers source info.
+	// TraceLog is used by trace logging functions that replace the source information with the callers source info.
 	TraceLog *slog.Logger //nolint: gochecknoglobals
 )
 
 func init() {
 	sourcePathDepth = setSourcePathDepth()
 	logSource = setSource()
-	LogLevel = setLogLevel()
+	logFormat.Store(setLogFormat())
+	setLogLevel()
 	TraceLog = TraceLogger(os.Stderr)
 }
 
-// setLogLevel returns the logging level selected by the user.
-func setLogLevel() slog.Level {
-	if tlevel, ok := os.LookupEnv(logLevelEnvVar); ok {
-		switch tlevel {
-		case "TRACE":
-			return LevelTrace
-		case "DEBUG":
-			return slog.LevelDebug
-		case "INFO":
-			return slog.LevelInfo
-		case "WARN":
-			return slog.LevelWarn
-		case "ERROR":
-			return slog.LevelError
-		case "FATAL":
-			return LevelFatal
-		default:
-			fmt.Printf("Invalid tracing level: %s, defaulting to INFO", tlevel)
+// setLogLevel sets the logging levels selected by the user.
+func setLogLevel() {
+	level, overrides := slog.LevelInfo, map[string]slog.Level(nil)
+	if spec, ok := os.LookupEnv(logLevelEnvVar); ok {
+		var err error
+		level, overrides, err = ParseLevelSpec(spec)
+		if err != nil {
+			fmt.Printf("Invalid tracing level: %s, defaulting to INFO", spec)
+			level, overrides = slog.LevelInfo, nil
 		}
 	}
-	return slog.LevelInfo
+	setPackageLevels(overrides)
+	LogLevel.Set(level)
 }
 
 // GetLogLevel gets the log level as set by environmental variable.
 func GetLogLevel() string {
-	return LogLevel.String()
+	return LevelName(LogLevel.Level())
 }
 
 // SetLogLevel sets the log level from environmental variable.
 func SetLogLevel() {
-	LogLevel = setLogLevel()
+	setLogLevel()
 }
 
 // GetLogOut gets the log output io.Writer.
@@ -147,34 +140,7 @@ func setLogLevelName(a slog.Attr) slog.Attr {
 			fmt.Printf("expected slog.LevelKey, invalid slog.Attr, Key: %s, Value: %s, skipping\n", a.Key, a.Value)
 			return a
 		}
-		levelLabel, exists := levelNames[level]
-		if !exists {
-			levelLabel = level.String()
-		}
-
-		a.Value = slog.StringValue(levelLabel)
-	}
-	return a
-}
-
-// setCallerSourceName is used to set to source information to the caller of the function calling log.
-func setCallerSourceName(a slog.Attr) slog.Attr {
-	if a.Key == slog.SourceKey { //nolint: nestif
-		source := GetCaller(eleven, false)
-		if sourcePathDepth >= 0 {
-			path := strings.Split(filepath.Dir(source.File), "/")
-			if len(path) < sourcePathDepth {
-				sourcePathDepth = len(path)
-			}
-			includedPath := strings.Join(path[len(path)-sourcePathDepth:], "/")
-			sep := ""
-			if len(includedPath) > 0 {
-				sep = "/"
-			}
-			source.File = fmt.Sprintf("%s%s%s", includedPath, sep, filepath.Base(source.File))
-			source.Function = filepath.Base(source.Function)
-		}
-		a.Value = slog.AnyValue(source)
+		a.Value = slog.StringValue(LevelName(level))
 	}
 	return a
 }
@@ -206,73 +172,75 @@ func setSourceName(a slog.Attr) slog.Attr {
 	return a
 }
 
-// NewLogger returns a JSON logger writing to provided writer.
+// NewLoggerTo returns a logger writing to provided writer using the format selected by LOG_FORMAT, JSON by default.
 func NewLoggerTo(out io.Writer) *slog.Logger {
-	return slog.New(slog.NewJSONHandler(out, setupOptions()))
+	return slog.New(wrapHandler(formatHandler(out, GetLogFormat(), logSource)))
+}
+
+// formatHandler returns a handler writing to out in the format specified, falling back to JSON for an invalid format.
+func formatHandler(out io.Writer, format Format, addSource bool) slog.Handler {
+	handler, err := newFormatHandler(out, format, levels, addSource)
+	if err != nil {
+		return slog.NewJSONHandler(out, setupOptions())
+	}
+	return handler
 }
 
 func setupOptions() *slog.HandlerOptions {
 	return &slog.HandlerOptions{
-		Level:     LogLevel,
+		Level:     levels,
 		AddSource: logSource,
-		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr { //nolint: revive
+		ReplaceAttr: withoutHighlight(func(groups []string, a slog.Attr) slog.Attr { //nolint: revive
 			a = setLogLevelName(a)
 			a = setSourceName(a)
 			return a
-		},
+		}),
 	}
 }
 
-// NewLogger returns a JSON logger.
+// wrapHandler adds the handlers used by all loggers created by this package to a handler.
+func wrapHandler(handler slog.Handler) slog.Handler {
+	return NewPackageLevelHandler(enrichHandler(handler))
+}
+
+// enrichHandler adds the handlers that add context attributes to and redact records to a handler.
+func enrichHandler(handler slog.Handler) slog.Handler {
+	return NewContextHandler(NewRedactHandler(handler, nil))
+}
+
+// NewLogger returns a logger writing to stdout using the format selected by LOG_FORMAT, JSON by default.
 func NewLogger() *slog.Logger {
-	return slog.New(slog.NewJSONHandler(os.Stdout, setupOptions()))
+	return NewLoggerTo(os.Stdout)
 }
 
-// traceLogger returns a logger for internal use by tracing that replaces the source details with supplied values.
+// TraceLogger returns a logger for internal use by tracing, the tracing functions set the source details to their caller.
+// The logger writes each record using the format set when it is logged, so it follows changes made by SetLogFormat.
 func TraceLogger(out io.Writer) *slog.Logger {
-	opts := &slog.HandlerOptions{
-		Level:     LogLevel,
-		AddSource: true,
-		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr { //nolint: revive
-			a = setLogLevelName(a)
-			a = setCallerSourceName(a)
-			return a
-		},
-	}
-
-	handler := slog.NewJSONHandler(out, opts)
-	return slog.New(handler)
+	return slog.New(wrapHandler(newCurrentFormatHandler(out, true)))
 }
 
-// NewTextLogger returns a text logger.
+// NewTextLoggerTo returns a text logger logging to provided output.
 func NewTextLoggerTo(out io.Writer) *slog.Logger {
-	opts := &slog.HandlerOptions{
-		Level: LogLevel,
-	}
-
-	handler := slog.NewTextHandler(out, opts)
-
-	return slog.New(handler)
+	return slog.New(wrapHandler(formatHandler(out, FormatText, false)))
 }
 
-// NewTextLoggerTo returns a text logger logging to provided output.
+// NewTextLogger returns a text logger.
 func NewTextLogger() *slog.Logger {
-	opts := &slog.HandlerOptions{
-		Level: LogLevel,
-	}
-
-	handler := slog.NewTextHandler(os.Stdout, opts)
-
-	return slog.New(handler)
+	return NewTextLoggerTo(os.Stdout)
 }
 
-// LogJSON is used log an item in JSON format.
+// LogJSON is used log an item in JSON format, sensitive data is redacted.
 func LogJSON(data interface{}) string {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return err.Error()
 	}
 
+	jsonData, err = GetRedactor().RedactJSON(jsonData)
+	if err != nil {
+		return err.Error()
+	}
+
 	var prettyJSON bytes.Buffer
 	err = json.Indent(&prettyJSON, jsonData, "", "  ")
 
@@ -312,6 +280,11 @@ func GetObjKindNamespaceName(obj k8sruntime.Object) (result []interface{}) {
 	return result
 }
 
+// ContextWithObj returns a context holding the object kind, namespace and name as log attributes.
+func ContextWithObj(ctx context.Context, obj k8sruntime.Object) context.Context {
+	return ContextWith(ctx, GetObjKindNamespaceName(obj)...)
+}
+
 // Callers returns an array of strings containing the function name, source filename and line
 // number for the caller of this function and its caller moving up the stack for as many levels as
 // are available or the number of levels specified by the levels parameter.
@@ -379,33 +352,56 @@ func CallerText(skip int) string {
 
 // TraceCall traces calls and exit for functions.
 func TraceCall() {
-	ctx := context.Background()
-	TraceLog.Log(ctx, LevelTrace, "Entering function")
+	logCaller(context.Background(), TraceLog, 1, LevelTrace, "Entering function")
 }
 
 // TraceExit traces calls and exit for functions.
 func TraceExit() {
-	ctx := context.Background()
-	TraceLog.Log(ctx, LevelTrace, "Exiting function")
+	logCaller(context.Background(), TraceLog, 1, LevelTrace, "Exiting function")
 }
 
-// TraceCallWithCtx traces calls and exit for functions.
+// TraceCallWithCtx traces calls and exit for functions, including the log attributes held by the context.
 func TraceCallWithCtx(ctx context.Context) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	TraceLog.Log(ctx, LevelTrace, "Entering function")
+	logCaller(ctx, TraceLog, 1, LevelTrace, "Entering function")
 }
 
-// TraceExitWithCtx traces calls and exit for functions.
+// TraceExitWithCtx traces calls and exit for functions, including the log attributes held by the context.
 func TraceExitWithCtx(ctx context.Context) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	TraceLog.Log(ctx, LevelTrace, "Exiting function")
+	logCaller(ctx, TraceLog, 1, LevelTrace, "Exiting function")
+}
+
+// logCaller logs a message with the source set to the function 'skip' levels above the caller of logCaller.
+func logCaller(ctx context.Context, log *slog.Logger, skip int, level slog.Level, msg string, args ...any) {
+	if !log.Enabled(ctx, level) {
+		return
+	}
+	logPC(ctx, log, callerPC(skip+1), level, msg, args...)
+}
+
+// callerPC returns the program counter of the caller 'skip' levels above the caller of callerPC.
+func callerPC(skip int) uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(skip+2, pcs[:]) //nolint: mnd // skip runtime.Callers and callerPC.
+	return pcs[0]
+}
+
+// logPC logs a message with the source set to the program counter specified.
+func logPC(ctx context.Context, log *slog.Logger, pc uintptr, level slog.Level, msg string, args ...any) {
+	if !log.Enabled(ctx, level) {
+		return
+	}
+	record := slog.NewRecord(time.Now(), level, msg, pc)
+	record.Add(args...)
+	_ = log.Handler().Handle(ctx, record) //nolint: errcheck
 }
 
-// ToJSON is used get data in JSON format.
+// ToJSON is used get data in JSON format, sensitive data is redacted.
 func ToJSON(log *slog.Logger, data interface{}) string {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
@@ -414,6 +410,13 @@ func ToJSON(log *slog.Logger, data interface{}) string {
 		return err.Error()
 	}
 
+	jsonData, err = GetRedactor().RedactJSON(jsonData)
+	if err != nil {
+		log.Warn("failed to redact json", "error", err)
+
+		return err.Error()
+	}
+
 	var prettyJSON bytes.Buffer
 
 	err = json.Indent(&prettyJSON, jsonData, "", "  ")
@@ -426,14 +429,16 @@ func ToJSON(log *slog.Logger, data interface{}) string {
 	return prettyJSON.String()
 }
 
-// Debug can be used to emit debug output.
+// Debug logs a formatted message at DEBUG level to the package log output with the source set to the caller.
+//
+// Deprecated: use a logger's DebugContext method with attributes, or Dump to log a value.
 func Debug(pattern string, args ...interface{}) {
-	if LogLevel <= slog.LevelDebug {
-		pattern := CallerText(MyCallersCaller) + pattern
-		fmt.Printf(pattern, args...)
-	}
+	logCaller(context.Background(), internalLogger(), 1, slog.LevelDebug, fmt.Sprintf(pattern, args...))
 }
 
+// ErrorReport returns an error wrapping err with the caller and text prepended to its message.
+//
+// Deprecated: use WrapError, which records the caller and attributes without flattening them into the message.
 func ErrorReport(text string, err error) error {
 	return fmt.Errorf("%s - %s, %w", CallerText(MyCallersCaller), text, err)
 }
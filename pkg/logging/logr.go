@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// LogrNameKey is the attribute key used for the name of a logr logger.
+const LogrNameKey = "logger"
+
+// LogrVerbosity maps a logr verbosity level, as used by V(), to a slog level.
+type LogrVerbosity func(v int) slog.Level
+
+var logrVerbosity atomic.Pointer[LogrVerbosity] //nolint: gochecknoglobals
+
+// DefaultLogrVerbosity maps V(0) to INFO, V(1) to V(4) to DEBUG and higher verbosity levels to TRACE.
+func DefaultLogrVerbosity(v int) slog.Level {
+	switch {
+	case v <= 0:
+		return slog.LevelInfo
+	case v <= 4: //nolint: mnd
+		return slog.LevelDebug
+	default:
+		return LevelTrace
+	}
+}
+
+// SetLogrVerbosity sets the mapping of logr verbosity levels to slog levels used by loggers returned by LogrLogger,
+// nil restores DefaultLogrVerbosity.
+func SetLogrVerbosity(verbosity LogrVerbosity) {
+	if verbosity == nil {
+		logrVerbosity.Store(nil)
+		return
+	}
+	logrVerbosity.Store(&verbosity)
+}
+
+// logrLevel returns the slog level for a logr verbosity level.
+func logrLevel(v int) slog.Level {
+	if verbosity := logrVerbosity.Load(); verbosity != nil {
+		return (*verbosity)(v)
+	}
+	return DefaultLogrVerbosity(v)
+}
+
+// LogrLogger returns a logr.Logger that writes to the handler of a slog logger, for use by controller-runtime and klog.
+func LogrLogger(log *slog.Logger) logr.Logger {
+	if log == nil {
+		log = NewLogger()
+	}
+	return logr.New(&logrSink{handler: log.Handler()})
+}
+
+// logrSink is a logr.LogSink that passes messages to a slog handler.
+type logrSink struct {
+	handler slog.Handler
+	name    string
+	depth   int
+}
+
+// Init records the number of call frames added by logr.
+func (s *logrSink) Init(info logr.RuntimeInfo) {
+	s.depth = info.CallDepth
+}
+
+// Enabled reports whether the handler handles messages at the verbosity level specified.
+func (s *logrSink) Enabled(level int) bool {
+	return s.handler.Enabled(context.Background(), logrLevel(level))
+}
+
+// Info logs a message at the slog level mapped from the verbosity level.
+func (s *logrSink) Info(level int, msg string, keysAndValues ...any) {
+	s.log(logrLevel(level), msg, keysAndValues)
+}
+
+// Error logs an error message at ERROR level.
+func (s *logrSink) Error(err error, msg string, keysAndValues ...any) {
+	s.log(slog.LevelError, msg, append([]any{slog.Any("error", err)}, keysAndValues...))
+}
+
+// WithValues returns a sink that includes the key/value pairs in every message.
+func (s *logrSink) WithValues(keysAndValues ...any) logr.LogSink {
+	sink := *s
+	sink.handler = s.handler.WithAttrs(argsToAttrs(keysAndValues))
+	return &sink
+}
+
+// WithName returns a sink with the name appended to its name.
+func (s *logrSink) WithName(name string) logr.LogSink {
+	sink := *s
+	if len(sink.name) > 0 {
+		sink.name += "/"
+	}
+	sink.name += name
+	return &sink
+}
+
+// WithCallDepth returns a sink that attributes messages to a caller further up the stack.
+func (s *logrSink) WithCallDepth(depth int) logr.LogSink {
+	sink := *s
+	sink.depth += depth
+	return &sink
+}
+
+// log passes a message to the handler with the source set to the caller of the logr.Logger method.
+func (s *logrSink) log(level slog.Level, msg string, keysAndValues []any) {
+	ctx := context.Background()
+	if !s.handler.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(s.depth+3, pcs[:]) //nolint: mnd // skip runtime.Callers, log and the Info or Error method.
+	record := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	if len(s.name) > 0 {
+		record.AddAttrs(slog.String(LogrNameKey, s.name))
+	}
+	record.Add(keysAndValues...)
+	_ = s.handler.Handle(ctx, record) //nolint: errcheck
+}
@@ -0,0 +1,210 @@
+// Package logtest provides a slog.Handler that records log messages so tests can assert on what was logged.
+package logtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/paul-carlton/goutils/pkg/logging"
+	"github.com/paul-carlton/goutils/pkg/miscutils"
+)
+
+// Record is a log message captured by a Recorder, attributes in groups are keyed by the group names and attribute key
+// joined with a dot, e.g. "request.method".
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   map[string]slog.Value
+}
+
+// store holds the records captured by a Recorder and the recorders derived from it.
+type store struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// Recorder is a slog.Handler that stores the records it handles.
+type Recorder struct {
+	store  *store
+	level  slog.Leveler
+	attrs  []slog.Attr
+	groups []string
+}
+
+// NewRecorder returns a Recorder that stores records at or above the level, or at all levels if level is nil.
+func NewRecorder(level slog.Leveler) *Recorder {
+	if level == nil {
+		level = slog.Level(-100) //nolint: mnd
+	}
+	return &Recorder{store: &store{}, level: level}
+}
+
+// NewObjParams returns object parameters with a logger that writes to a new Recorder, for use when creating the
+// objects under test. Output written to LogOut is discarded.
+func NewObjParams(level slog.Leveler) (*miscutils.NewObjParams, *Recorder) {
+	recorder := NewRecorder(level)
+	return &miscutils.NewObjParams{
+		Ctx:    context.Background(),
+		Log:    recorder.Logger(),
+		LogOut: io.Discard,
+	}, recorder
+}
+
+// Logger returns a logger that writes to the recorder, adding context attributes and redacting sensitive data in
+// the same way as loggers created by the logging package.
+func (r *Recorder) Logger() *slog.Logger {
+	return slog.New(logging.NewContextHandler(logging.NewRedactHandler(r, nil)))
+}
+
+// Enabled reports whether the recorder stores records at the level.
+func (r *Recorder) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= r.level.Level()
+}
+
+// Handle stores the record.
+func (r *Recorder) Handle(_ context.Context, record slog.Record) error {
+	rec := Record{Time: record.Time, Level: record.Level, Message: record.Message, Attrs: map[string]slog.Value{}}
+	for _, attr := range r.attrs {
+		addAttr(rec.Attrs, "", attr)
+	}
+	prefix := strings.Join(r.groups, ".")
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(rec.Attrs, prefix, attr)
+		return true
+	})
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.records = append(r.store.records, rec)
+	return nil
+}
+
+// WithAttrs returns a Recorder sharing the stored records that adds the attributes to each record.
+func (r *Recorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	recorder := *r
+	prefix := strings.Join(r.groups, ".")
+	recorder.attrs = append([]slog.Attr{}, r.attrs...)
+	for _, attr := range attrs {
+		if len(prefix) > 0 {
+			attr.Key = prefix + "." + attr.Key
+		}
+		recorder.attrs = append(recorder.attrs, attr)
+	}
+	return &recorder
+}
+
+// WithGroup returns a Recorder sharing the stored records that adds attributes to the group.
+func (r *Recorder) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return r
+	}
+	recorder := *r
+	recorder.groups = append(append([]string{}, r.groups...), name)
+	return &recorder
+}
+
+// Records returns the records stored.
+func (r *Recorder) Records() []Record {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return append([]Record{}, r.store.records...)
+}
+
+// Reset removes the records stored.
+func (r *Recorder) Reset() {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.records = nil
+}
+
+// Find returns the records at the level whose message contains msg and that have all of the attributes, which are
+// key/value pairs and/or slog.Attr values.
+func (r *Recorder) Find(level slog.Level, msg string, attrs ...any) []Record {
+	expected := map[string]slog.Value{}
+	record := slog.NewRecord(time.Time{}, level, msg, 0)
+	record.Add(attrs...)
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(expected, "", attr)
+		return true
+	})
+
+	found := []Record{}
+	for _, rec := range r.Records() {
+		if rec.Level == level && strings.Contains(rec.Message, msg) && hasAttrs(rec.Attrs, expected) {
+			found = append(found, rec)
+		}
+	}
+	return found
+}
+
+// AssertLogged reports a test error if no record at the level whose message contains msg and that has the attributes
+// was logged.
+func (r *Recorder) AssertLogged(t testing.TB, level slog.Level, msg string, attrs ...any) bool {
+	t.Helper()
+	if len(r.Find(level, msg, attrs...)) > 0 {
+		return true
+	}
+	t.Errorf("\nExpected: %s message containing: %q with attributes: %v\nGot.....:\n%s", logging.LevelName(level), msg, attrs, r)
+	return false
+}
+
+// AssertNotLogged reports a test error if a record at the level whose message contains msg and that has the
+// attributes was logged.
+func (r *Recorder) AssertNotLogged(t testing.TB, level slog.Level, msg string, attrs ...any) bool {
+	t.Helper()
+	found := r.Find(level, msg, attrs...)
+	if len(found) == 0 {
+		return true
+	}
+	t.Errorf("\nExpected: no %s message containing: %q with attributes: %v\nGot.....: %v", logging.LevelName(level), msg, attrs, found)
+	return false
+}
+
+// String returns the stored records, one per line.
+func (r *Recorder) String() string {
+	lines := []string{}
+	for _, rec := range r.Records() {
+		lines = append(lines, fmt.Sprintf("%s %q %v", logging.LevelName(rec.Level), rec.Message, rec.Attrs))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// addAttr adds an attribute to a map, flattening groups into dot separated keys.
+func addAttr(attrs map[string]slog.Value, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	key := attr.Key
+	if len(prefix) > 0 && len(key) > 0 {
+		key = prefix + "." + key
+	} else if len(prefix) > 0 {
+		key = prefix
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		for _, a := range attr.Value.Group() {
+			addAttr(attrs, key, a)
+		}
+		return
+	}
+	attrs[key] = attr.Value
+}
+
+// hasAttrs reports whether the attributes include all of the expected attributes, values are equal if they are the
+// same or have the same string representation.
+func hasAttrs(attrs, expected map[string]slog.Value) bool {
+	for key, value := range expected {
+		actual, ok := attrs[key]
+		if !ok || !(actual.Equal(value) || actual.String() == value.String()) {
+			return false
+		}
+	}
+	return true
+}
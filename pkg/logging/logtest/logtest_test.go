@@ -0,0 +1,55 @@
+package logtest_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/paul-carlton/goutils/pkg/logging"
+	"github.com/paul-carlton/goutils/pkg/logging/logtest"
+	"github.com/paul-carlton/goutils/pkg/miscutils"
+)
+
+func TestRecorder(t *testing.T) {
+	o, recorder := logtest.NewObjParams(nil)
+	ctx := logging.ContextWith(o.Ctx, logging.RunIDKey, "run-1")
+
+	o.Log.With("component", "test").WithGroup("request").DebugContext(ctx, "sending request", "method", "GET", "token", "abc")
+	o.Log.Error("request failed", "error", io.EOF, slog.Int("attempt", 2))
+	miscutils.LogWarning(o, "cluster not ready")
+
+	tests := []struct {
+		testNum int
+		level   slog.Level
+		msg     string
+		attrs   []any
+		logged  bool
+	}{
+		{1, slog.LevelDebug, "sending request", []any{"component", "test", "request.method", "GET", "request." + logging.RunIDKey, "run-1"}, true},
+		{2, slog.LevelDebug, "sending", []any{"request.token", logging.RedactedValue}, true},
+		{3, slog.LevelError, "request failed", []any{"error", io.EOF, "attempt", 2}, true},
+		{4, slog.LevelError, "request failed", []any{"error", "EOF"}, true},
+		{5, slog.LevelWarn, "cluster not ready", nil, true},
+		{6, slog.LevelInfo, "sending request", nil, false},
+		{7, slog.LevelError, "request failed", []any{"attempt", 3}, false},
+	}
+
+	for _, test := range tests {
+		if test.logged {
+			recorder.AssertLogged(t, test.level, test.msg, test.attrs...)
+		} else {
+			recorder.AssertNotLogged(t, test.level, test.msg, test.attrs...)
+		}
+	}
+
+	recorder.Reset()
+	if records := recorder.Records(); len(records) != 0 {
+		t.Errorf("\nExpected: no records after reset\nGot.....: %v", records)
+	}
+
+	info := logtest.NewRecorder(slog.LevelInfo)
+	if info.Enabled(context.Background(), slog.LevelDebug) {
+		t.Errorf("\nExpected: DEBUG not enabled at INFO")
+	}
+}
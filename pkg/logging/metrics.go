@@ -0,0 +1,292 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	// RecordsMetricName is the name of the metric counting records by level, logger and attribute.
+	RecordsMetricName = "log_records_total"
+	// SitesMetricName is the name of the metric counting WARN and ERROR records by source location.
+	SitesMetricName = "log_sites_total"
+
+	// DefaultTopSites is the number of source locations reported by the metrics http.Handler if not specified.
+	DefaultTopSites = 20
+	// DefaultMaxValues is the number of distinct attribute values counted if not specified.
+	DefaultMaxValues = 100
+	// OtherValue is the attribute value records are counted under once the maximum number of values is reached.
+	OtherValue = "other"
+
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// labelValueEscaper escapes the characters that are not allowed in Prometheus label values.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`) //nolint: gochecknoglobals
+
+// MetricsConfig holds the configuration of a MetricsHandler.
+type MetricsConfig struct {
+	AttrKey   string // An attribute to count records by, e.g. component, nested keys are dot separated.
+	TopSites  int    // The number of source locations reported by the http.Handler, defaults to DefaultTopSites.
+	MaxValues int    // The number of distinct attribute values counted, defaults to DefaultMaxValues.
+}
+
+// RecordCount is the number of records logged with a level, logger name and attribute value.
+type RecordCount struct {
+	Level  string
+	Logger string
+	Value  string
+	Count  uint64
+}
+
+// SiteCount is the number of WARN or ERROR records logged at a source location.
+type SiteCount struct {
+	Level  string
+	Source string // The source file and line, e.g. deployment.go:42.
+	Count  uint64
+}
+
+// MetricsSnapshot holds the counts reported by a MetricsHandler at a point in time.
+type MetricsSnapshot struct {
+	Records []RecordCount // Sorted by level, logger and value.
+	Sites   []SiteCount   // Sorted by count, highest first.
+}
+
+// metricsState is shared by a MetricsHandler and the handlers derived from it.
+type metricsState struct {
+	config  MetricsConfig
+	mu      sync.Mutex
+	records map[RecordCount]uint64
+	sites   map[SiteCount]uint64
+	values  map[string]struct{} // The attribute values counted.
+}
+
+// MetricsHandler is a slog.Handler that counts the records passed to the next handler.
+type MetricsHandler struct {
+	next   slog.Handler
+	state  *metricsState
+	prefix string
+	logger string
+	value  string
+}
+
+// NewMetricsHandler returns a handler that counts records by level, logger name and the configured attribute and counts
+// WARN and ERROR records by source location. The logger name is the value of the logger attribute set by LogrLogger,
+// or the package the record was logged from. To bound the number of series, records with attribute values beyond the
+// first MaxValues distinct values are counted under OtherValue.
+func NewMetricsHandler(next slog.Handler, config MetricsConfig) *MetricsHandler {
+	if config.TopSites <= 0 {
+		config.TopSites = DefaultTopSites
+	}
+	if config.MaxValues <= 0 {
+		config.MaxValues = DefaultMaxValues
+	}
+	return &MetricsHandler{
+		next: next,
+		state: &metricsState{
+			config:  config,
+			records: map[RecordCount]uint64{},
+			sites:   map[SiteCount]uint64{},
+			values:  map[string]struct{}{},
+		},
+	}
+}
+
+// NewMetricsLoggerTo returns a logger writing to provided writer using the format selected by LOG_FORMAT that counts
+// the records logged, together with its MetricsHandler so the caller can expose the counts.
+func NewMetricsLoggerTo(out io.Writer, config MetricsConfig) (*slog.Logger, *MetricsHandler) {
+	handler := NewMetricsHandler(formatHandler(out, GetLogFormat(), logSource), config)
+	return slog.New(wrapHandler(handler)), handler
+}
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *MetricsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle counts the record and passes it to the next handler.
+func (h *MetricsHandler) Handle(ctx context.Context, r slog.Record) error {
+	logger, value := h.logger, h.value
+	r.Attrs(func(a slog.Attr) bool {
+		h.match(h.prefix, a, &logger, &value)
+		return true
+	})
+	if logger == "" {
+		logger = packageOfPC(r.PC)
+	}
+
+	level := LevelName(r.Level)
+	s := h.state
+	s.mu.Lock()
+	s.records[RecordCount{Level: level, Logger: logger, Value: s.bucket(value)}]++
+	if r.Level >= slog.LevelWarn {
+		s.sites[SiteCount{Level: level, Source: siteOf(r.PC)}]++
+	}
+	s.mu.Unlock()
+
+	return h.next.Handle(ctx, r)
+}
+
+// bucket returns the value records are counted under, recording it if the maximum number of values has not been reached.
+// The caller must hold the lock.
+func (s *metricsState) bucket(value string) string {
+	if value == "" {
+		return value
+	}
+	if _, ok := s.values[value]; ok {
+		return value
+	}
+	if len(s.values) >= s.config.MaxValues {
+		return OtherValue
+	}
+	s.values[value] = struct{}{}
+	return value
+}
+
+// WithAttrs returns a new MetricsHandler sharing the counts whose next handler has the given attributes.
+func (h *MetricsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler := *h
+	handler.next = h.next.WithAttrs(attrs)
+	for _, a := range attrs {
+		h.match(h.prefix, a, &handler.logger, &handler.value)
+	}
+	return &handler
+}
+
+// WithGroup returns a new MetricsHandler sharing the counts whose next handler has the given group.
+func (h *MetricsHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	handler := *h
+	handler.next = h.next.WithGroup(name)
+	handler.prefix = h.prefix + name + "."
+	return &handler
+}
+
+// Snapshot returns the current counts.
+func (h *MetricsHandler) Snapshot() MetricsSnapshot {
+	s := h.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := MetricsSnapshot{
+		Records: make([]RecordCount, 0, len(s.records)),
+		Sites:   make([]SiteCount, 0, len(s.sites)),
+	}
+	for key, count := range s.records {
+		key.Count = count
+		snapshot.Records = append(snapshot.Records, key)
+	}
+	for key, count := range s.sites {
+		key.Count = count
+		snapshot.Sites = append(snapshot.Sites, key)
+	}
+	slices.SortFunc(snapshot.Records, func(a, b RecordCount) int {
+		return strings.Compare(a.Level+"\x00"+a.Logger+"\x00"+a.Value, b.Level+"\x00"+b.Logger+"\x00"+b.Value)
+	})
+	slices.SortFunc(snapshot.Sites, func(a, b SiteCount) int {
+		if a.Count != b.Count {
+			if a.Count > b.Count {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Source, b.Source)
+	})
+	return snapshot
+}
+
+// TopSites returns up to n of the source locations with the most WARN and ERROR records.
+func (s MetricsSnapshot) TopSites(n int) []SiteCount {
+	if n < len(s.Sites) {
+		return s.Sites[:n]
+	}
+	return s.Sites
+}
+
+// HTTPHandler returns an http.Handler that writes the counts in the Prometheus text exposition format.
+func (h *MetricsHandler) HTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", metricsContentType)
+		_, _ = w.Write(h.prometheusText()) //nolint: errcheck
+	})
+}
+
+// prometheusText returns the counts in the Prometheus text exposition format.
+func (h *MetricsHandler) prometheusText() []byte {
+	snapshot := h.Snapshot()
+	attrLabel := metricLabelName(h.state.config.AttrKey)
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "# HELP %s Number of log records by level and logger.\n", RecordsMetricName)
+	fmt.Fprintf(buf, "# TYPE %s counter\n", RecordsMetricName)
+	for _, record := range snapshot.Records {
+		fmt.Fprintf(buf, `%s{level="%s",logger="%s"`, RecordsMetricName, metricLabelValue(record.Level), metricLabelValue(record.Logger))
+		if len(attrLabel) > 0 {
+			fmt.Fprintf(buf, `,%s="%s"`, attrLabel, metricLabelValue(record.Value))
+		}
+		fmt.Fprintf(buf, "} %d\n", record.Count)
+	}
+
+	fmt.Fprintf(buf, "# HELP %s Number of WARN and ERROR log records by source location.\n", SitesMetricName)
+	fmt.Fprintf(buf, "# TYPE %s counter\n", SitesMetricName)
+	for _, site := range snapshot.TopSites(h.state.config.TopSites) {
+		fmt.Fprintf(buf, "%s{level=\"%s\",source=\"%s\"} %d\n",
+			SitesMetricName, metricLabelValue(site.Level), metricLabelValue(site.Source), site.Count)
+	}
+	return buf.Bytes()
+}
+
+// match sets the logger name or attribute value if the attribute, or an attribute in a group, has the key required.
+func (h *MetricsHandler) match(prefix string, a slog.Attr, logger, value *string) {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		if len(a.Key) > 0 {
+			prefix += a.Key + "."
+		}
+		for _, attr := range a.Value.Group() {
+			h.match(prefix, attr, logger, value)
+		}
+		return
+	}
+	switch prefix + a.Key {
+	case LogrNameKey:
+		*logger = a.Value.String()
+	case h.state.config.AttrKey:
+		*value = a.Value.String()
+	}
+}
+
+// siteOf returns the short source file name and line of a program counter, as reported by GetCaller.
+func siteOf(pc uintptr) string {
+	if pc == 0 {
+		return "not available"
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+}
+
+// metricLabelName returns an attribute key as a valid Prometheus label name.
+func metricLabelName(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// metricLabelValue returns a label value with backslash, double quote and newline characters escaped.
+func metricLabelValue(value string) string {
+	return labelValueEscaper.Replace(strings.ToValidUTF8(value, "\uFFFD"))
+}
@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+)
+
+// SinkConfig holds the configuration of a log output used by NewMultiLogger.
+type SinkConfig struct {
+	Writer    io.Writer    // The writer to send log output to.
+	Format    Format       // The output format, defaults to JSON.
+	Level     slog.Leveler // The minimum level written to this sink, if nil the package logging levels are used.
+	AddSource bool         // Include source file information.
+}
+
+// MultiHandler is a slog.Handler that passes records to multiple handlers.
+type MultiHandler struct {
+	handlers []slog.Handler
+}
+
+// NewMultiHandler returns a handler that passes each record to all of the handlers that are enabled for its level.
+func NewMultiHandler(handlers ...slog.Handler) *MultiHandler {
+	return &MultiHandler{handlers: handlers}
+}
+
+// NewMultiLogger returns a logger that writes to multiple sinks, each with its own writer, format, level and source setting.
+func NewMultiLogger(sinks ...SinkConfig) (*slog.Logger, error) {
+	handlers := make([]slog.Handler, 0, len(sinks))
+	for _, sink := range sinks {
+		level := sink.Level
+		if level == nil {
+			level = levels
+		}
+		handler, err := newFormatHandler(sink.Writer, sink.Format, level, sink.AddSource)
+		if err != nil {
+			return nil, err
+		}
+		if sink.Level == nil {
+			handler = NewPackageLevelHandler(handler)
+		}
+		handlers = append(handlers, handler)
+	}
+	return slog.New(enrichHandler(NewMultiHandler(handlers...))), nil
+}
+
+// Enabled reports whether any of the handlers handle records at the given level.
+func (h *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle passes the record to each of the handlers enabled for its level, returning any errors they report.
+func (h *MultiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := handler.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a new MultiHandler whose handlers have the given attributes.
+func (h *MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, 0, len(h.handlers))
+	for _, handler := range h.handlers {
+		handlers = append(handlers, handler.WithAttrs(attrs))
+	}
+	return NewMultiHandler(handlers...)
+}
+
+// WithGroup returns a new MultiHandler whose handlers have the given group.
+func (h *MultiHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	handlers := make([]slog.Handler, 0, len(h.handlers))
+	for _, handler := range h.handlers {
+		handlers = append(handlers, handler.WithGroup(name))
+	}
+	return NewMultiHandler(handlers...)
+}
@@ -0,0 +1,188 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// packageLevels holds the per-package logging level overrides, it is replaced rather than updated when the overrides change.
+type packageLevels struct {
+	overrides map[string]slog.Level
+	min       slog.Level
+}
+
+// levelLeveler implements slog.Leveler, returning the lowest level enabled for any package.
+type levelLeveler struct{}
+
+var (
+	// levels is the slog.Leveler used by handlers created by this package.
+	levels = levelLeveler{} //nolint: gochecknoglobals
+
+	pkgLevels atomic.Pointer[packageLevels] //nolint: gochecknoglobals
+)
+
+// Level returns the lowest logging level enabled for any package, handlers use this to determine if a record could be
+// logged, the PackageLevelHandler then applies the level for the package that generated the record.
+func (levelLeveler) Level() slog.Level {
+	level := LogLevel.Level()
+	if p := pkgLevels.Load(); p != nil && p.min < level {
+		return p.min
+	}
+	return level
+}
+
+// ParseLevelSpec parses a logging level specification, e.g. "INFO,httpclient=TRACE,k8s=DEBUG", returning the default
+// level and any per-package overrides. Packages are identified by the final element(s) of their import path.
+func ParseLevelSpec(spec string) (slog.Level, map[string]slog.Level, error) {
+	level := slog.LevelInfo
+	overrides := map[string]slog.Level{}
+	for _, item := range strings.Split(spec, ",") {
+		if len(strings.TrimSpace(item)) == 0 {
+			continue
+		}
+		pkg, name, found := strings.Cut(item, "=")
+		if !found {
+			var err error
+			if level, err = ParseLevel(item); err != nil {
+				return slog.LevelInfo, nil, err
+			}
+			continue
+		}
+		pkgLevel, err := ParseLevel(name)
+		if err != nil {
+			return slog.LevelInfo, nil, err
+		}
+		pkg = strings.Trim(strings.TrimSpace(pkg), "/")
+		if len(pkg) == 0 {
+			return slog.LevelInfo, nil, fmt.Errorf("%w: missing package name in %s", errInvalidLevel, item)
+		}
+		overrides[pkg] = pkgLevel
+	}
+	return level, overrides, nil
+}
+
+// SetLevelSpec sets the default logging level and per-package overrides from a logging level specification.
+func SetLevelSpec(spec string) error {
+	level, overrides, err := ParseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+	setPackageLevels(overrides)
+	SetLevel(level)
+	return nil
+}
+
+// GetLevelSpec returns the current logging level specification including any per-package overrides.
+func GetLevelSpec() string {
+	items := []string{GetLogLevel()}
+	if p := pkgLevels.Load(); p != nil {
+		pkgs := make([]string, 0, len(p.overrides))
+		for pkg := range p.overrides {
+			pkgs = append(pkgs, pkg)
+		}
+		sort.Strings(pkgs)
+		for _, pkg := range pkgs {
+			items = append(items, fmt.Sprintf("%s=%s", pkg, LevelName(p.overrides[pkg])))
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// setPackageLevels replaces the per-package logging level overrides.
+func setPackageLevels(overrides map[string]slog.Level) {
+	if len(overrides) == 0 {
+		pkgLevels.Store(nil)
+		return
+	}
+	p := &packageLevels{overrides: make(map[string]slog.Level, len(overrides)), min: LevelFatal}
+	for pkg, level := range overrides {
+		p.overrides[pkg] = level
+		p.min = min(p.min, level)
+	}
+	pkgLevels.Store(p)
+}
+
+// LevelFor returns the logging level for a package import path, the override with the longest matching
+// package name is used, if there is no matching override the default level is returned.
+func LevelFor(pkgPath string) slog.Level {
+	p := pkgLevels.Load()
+	if p == nil {
+		return LogLevel.Level()
+	}
+	level, matched := LogLevel.Level(), ""
+	for pkg, pkgLevel := range p.overrides {
+		if len(pkg) > len(matched) && (pkgPath == pkg || strings.HasSuffix(pkgPath, "/"+pkg)) {
+			level, matched = pkgLevel, pkg
+		}
+	}
+	return level
+}
+
+// Enabled reports whether the logging level is enabled for the package of the function calling Enabled.
+func Enabled(level slog.Level) bool {
+	if pkgLevels.Load() == nil {
+		return level >= LogLevel.Level()
+	}
+	pc, _, _, ok := runtime.Caller(1)
+	if !ok {
+		return level >= LogLevel.Level()
+	}
+	return level >= LevelFor(packageOfPC(pc))
+}
+
+// packageOfPC returns the import path of the package containing the function for a program counter.
+func packageOfPC(pc uintptr) string {
+	if pc == 0 {
+		return ""
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return packageOf(frame.Function)
+}
+
+// packageOf returns the import path of the package from a fully qualified function name,
+// e.g. github.com/paul-carlton/goutils/pkg/httpclient.(*reqResp).HTTPreq.
+func packageOf(funcName string) string {
+	lastSlash := strings.LastIndex(funcName, "/")
+	if dot := strings.Index(funcName[lastSlash+1:], "."); dot >= 0 {
+		return funcName[:lastSlash+1+dot]
+	}
+	return funcName
+}
+
+// PackageLevelHandler is a slog.Handler that drops records below the logging level of the package that generated them.
+type PackageLevelHandler struct {
+	next slog.Handler
+}
+
+// NewPackageLevelHandler returns a handler that applies per-package logging levels before passing records to the next handler.
+func NewPackageLevelHandler(next slog.Handler) *PackageLevelHandler {
+	return &PackageLevelHandler{next: next}
+}
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *PackageLevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle passes the record to the next handler if its level is enabled for the package that generated it.
+func (h *PackageLevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	if pkgLevels.Load() != nil && r.Level < LevelFor(packageOfPC(r.PC)) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a new PackageLevelHandler whose next handler has the given attributes.
+func (h *PackageLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewPackageLevelHandler(h.next.WithAttrs(attrs))
+}
+
+// WithGroup returns a new PackageLevelHandler whose next handler has the given group.
+func (h *PackageLevelHandler) WithGroup(name string) slog.Handler {
+	return NewPackageLevelHandler(h.next.WithGroup(name))
+}
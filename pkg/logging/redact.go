@@ -0,0 +1,300 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// RedactedValue is the value logged in place of sensitive data.
+const RedactedValue = "[REDACTED]"
+
+var (
+	// DefaultRedactKeys are the attribute and JSON field names whose values are redacted by default.
+	// Names are matched case insensitively ignoring '_' and '-' characters, a key is redacted if it contains one of these names.
+	DefaultRedactKeys = []string{ //nolint: gochecknoglobals
+		"token", "password", "passwd", "secret", "authorization", "apikey", "accesskey", "credential", "creds", "privatekey",
+	}
+
+	// DefaultRedactPatterns are the patterns used to find and redact sensitive data in log messages and string values by default.
+	DefaultRedactPatterns = []*regexp.Regexp{ //nolint: gochecknoglobals
+		regexp.MustCompile(`gh[pousr]_[A-Za-z0-9]{36,}`),
+		regexp.MustCompile(`github_pat_[A-Za-z0-9_]{22,}`),
+		regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`),
+		regexp.MustCompile(`(?i)\b(bearer|basic)\s+([A-Za-z0-9\-._~+/]{16,}=*|[A-Za-z0-9\-._~+/]+=+)`),
+		regexp.MustCompile(`k8s-aws-v1\.[A-Za-z0-9_\-]+`),
+		regexp.MustCompile(`hooks\.slack\.com/services/[A-Za-z0-9/]+`),
+		regexp.MustCompile(`xox[abprs]-[A-Za-z0-9\-]+`),
+		regexp.MustCompile(`://[^:/\s]+:[^@/\s]+@`),
+	}
+
+	redactor atomic.Pointer[Redactor] //nolint: gochecknoglobals
+)
+
+func init() {
+	redactor.Store(NewRedactor(DefaultRedactKeys, DefaultRedactPatterns))
+}
+
+// Secret is a string type for sensitive values, it is always rendered masked when logged or marshalled to JSON.
+type Secret string
+
+// LogValue implements slog.LogValuer, returning the redacted value.
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(RedactedValue)
+}
+
+// String implements fmt.Stringer, returning the redacted value.
+func (s Secret) String() string {
+	return RedactedValue
+}
+
+// MarshalJSON implements json.Marshaler, returning the redacted value.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RedactedValue)
+}
+
+// Redactable is implemented by types whose logged values are always marshalled to JSON and redacted, e.g. structs
+// with fields whose names are not sensitive keys holding text that may match the redaction patterns, such as URLs.
+type Redactable interface {
+	Redactable()
+}
+
+// Redactor holds the key names and patterns used to identify sensitive data.
+type Redactor struct {
+	keys     []string
+	patterns []*regexp.Regexp
+	types    sync.Map // Whether values of a type may hold sensitive data, keyed by reflect.Type.
+}
+
+// NewRedactor returns a Redactor that redacts values of the named keys and text matching the patterns.
+func NewRedactor(keys []string, patterns []*regexp.Regexp) *Redactor {
+	r := &Redactor{patterns: patterns}
+	for _, key := range keys {
+		r.keys = append(r.keys, normalizeKey(key))
+	}
+	return r
+}
+
+// SetRedactor sets the Redactor used by LogJSON, ToJSON and handlers created by this package.
+func SetRedactor(r *Redactor) {
+	redactor.Store(r)
+}
+
+// GetRedactor returns the Redactor used by LogJSON, ToJSON and handlers created by this package.
+func GetRedactor() *Redactor {
+	return redactor.Load()
+}
+
+// normalizeKey returns a key in lower case with '_' and '-' characters removed.
+func normalizeKey(key string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+}
+
+// IsSensitiveKey reports whether values of the named key should be redacted.
+func (r *Redactor) IsSensitiveKey(key string) bool {
+	key = normalizeKey(key)
+	for _, k := range r.keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactString returns the text with any sensitive data matching the patterns replaced.
+func (r *Redactor) RedactString(text string) string {
+	for _, p := range r.patterns {
+		text = p.ReplaceAllString(text, RedactedValue)
+	}
+	return text
+}
+
+// RedactAttr returns the attribute with sensitive values redacted.
+func (r *Redactor) RedactAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	if r.IsSensitiveKey(a.Key) {
+		a.Value = slog.StringValue(RedactedValue)
+		return a
+	}
+	switch a.Value.Kind() { //nolint: exhaustive
+	case slog.KindString:
+		a.Value = slog.StringValue(r.RedactString(a.Value.String()))
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+		redacted := make([]slog.Attr, 0, len(attrs))
+		for _, attr := range attrs {
+			redacted = append(redacted, r.RedactAttr(attr))
+		}
+		a.Value = slog.GroupValue(redacted...)
+	case slog.KindAny:
+		a.Value = r.redactAny(a.Value.Any())
+	}
+	return a
+}
+
+// redactAny returns a value with sensitive data redacted. Errors are logged as their redacted message. Values that may
+// hold sensitive data, see mayHoldSecrets, are marshalled to JSON and redacted, or if they cannot be marshalled, logged
+// as a redacted string. Other values are logged unchanged.
+func (r *Redactor) redactAny(value any) slog.Value {
+	if err, ok := value.(error); ok {
+		return slog.StringValue(r.RedactString(err.Error()))
+	}
+	if _, ok := value.(Redactable); !ok && !r.mayHoldSecrets(reflect.TypeOf(value)) {
+		return slog.AnyValue(value)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return slog.StringValue(r.RedactString(fmt.Sprint(value)))
+	}
+	if data, err = r.RedactJSON(data); err != nil {
+		return slog.StringValue(r.RedactString(fmt.Sprint(value)))
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var redacted any
+	if err := decoder.Decode(&redacted); err != nil {
+		return slog.StringValue(string(data))
+	}
+	return slog.AnyValue(redacted)
+}
+
+// mayHoldSecrets reports whether values of a type may hold sensitive data: maps, slices and arrays other than those of
+// numbers and booleans, interfaces, types implementing Redactable and structs with an exported field that has a
+// sensitive name or a type that may hold sensitive data. The result is cached for each type.
+func (r *Redactor) mayHoldSecrets(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	if cached, ok := r.types.Load(t); ok {
+		return cached.(bool) //nolint: forcetypeassert
+	}
+	result := r.typeMayHoldSecrets(t, map[reflect.Type]bool{})
+	r.types.Store(t, result)
+	return result
+}
+
+// typeMayHoldSecrets implements mayHoldSecrets without caching, types already being checked are skipped so that types
+// referring to themselves are only checked once.
+func (r *Redactor) typeMayHoldSecrets(t reflect.Type, checking map[reflect.Type]bool) bool {
+	if checking[t] {
+		return false
+	}
+	checking[t] = true
+	if t.Implements(reflect.TypeFor[Redactable]()) {
+		return true
+	}
+	switch t.Kind() { //nolint: exhaustive
+	case reflect.Pointer:
+		return r.typeMayHoldSecrets(t.Elem(), checking)
+	case reflect.Interface, reflect.Map:
+		return true
+	case reflect.Slice, reflect.Array:
+		switch t.Elem().Kind() { //nolint: exhaustive
+		case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint,
+			reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+			return false
+		}
+		return true
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if r.IsSensitiveKey(field.Name) || r.IsSensitiveKey(name) || r.typeMayHoldSecrets(field.Type, checking) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RedactJSON returns JSON data with the values of sensitive keys and text matching the patterns redacted.
+func (r *Redactor) RedactJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(r.redactValue(value))
+}
+
+// redactValue redacts decoded JSON data.
+func (r *Redactor) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if r.IsSensitiveKey(key) {
+				v[key] = RedactedValue
+				continue
+			}
+			v[key] = r.redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.redactValue(item)
+		}
+	case string:
+		return r.RedactString(v)
+	}
+	return value
+}
+
+// RedactHandler is a slog.Handler that redacts sensitive data from records before passing them to the next handler.
+type RedactHandler struct {
+	next     slog.Handler
+	redactor *Redactor
+}
+
+// NewRedactHandler returns a handler that redacts records using the Redactor supplied or, if nil, the package Redactor.
+func NewRedactHandler(next slog.Handler, r *Redactor) *RedactHandler {
+	return &RedactHandler{next: next, redactor: r}
+}
+
+// getRedactor returns the Redactor used by the handler.
+func (h *RedactHandler) getRedactor() *Redactor {
+	if h.redactor != nil {
+		return h.redactor
+	}
+	return GetRedactor()
+}
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *RedactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle redacts the record message and attributes and passes it to the next handler.
+func (h *RedactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redactor := h.getRedactor()
+	redacted := slog.NewRecord(r.Time, r.Level, redactor.RedactString(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(redactor.RedactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+// WithAttrs returns a new RedactHandler whose next handler has the given attributes, redacted.
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactor := h.getRedactor()
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		redacted = append(redacted, redactor.RedactAttr(a))
+	}
+	return NewRedactHandler(h.next.WithAttrs(redacted), h.redactor)
+}
+
+// WithGroup returns a new RedactHandler whose next handler has the given group.
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	return NewRedactHandler(h.next.WithGroup(name), h.redactor)
+}
@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+)
+
+// DefaultRingSize is the number of records retained by a RingHandler if no size is specified.
+const DefaultRingSize = 1000
+
+// ringEntry is a record held in a ring buffer together with the handler and context it was logged with.
+type ringEntry struct {
+	ctx     context.Context //nolint: containedctx
+	handler slog.Handler
+	record  slog.Record
+	written bool
+}
+
+// ringBuffer holds the most recent records logged using a RingHandler and the handlers derived from it.
+type ringBuffer struct {
+	mu      sync.Mutex
+	entries []ringEntry
+	next    int
+	full    bool
+}
+
+// RingHandler is a slog.Handler that retains the most recent records at all levels in memory while only passing records
+// at or above its level to the next handler. The retained records are written when an ERROR or FATAL record is logged.
+type RingHandler struct {
+	next  slog.Handler
+	level slog.Leveler
+	ring  *ringBuffer
+}
+
+// NewRingHandler returns a handler retaining the last size records, records at or above the level are passed to the next
+// handler immediately. If level is nil the package logging levels are used.
+func NewRingHandler(next slog.Handler, size int, level slog.Leveler) *RingHandler {
+	if size <= 0 {
+		size = DefaultRingSize
+	}
+	return &RingHandler{next: next, level: level, ring: &ringBuffer{entries: make([]ringEntry, size)}}
+}
+
+// NewRingLogger returns a logger writing to the provided writer using the format selected by LOG_FORMAT that retains
+// the last size records, writing them when an error is logged.
+func NewRingLogger(out io.Writer, size int) *slog.Logger {
+	return slog.New(enrichHandler(NewRingHandler(formatHandler(out, GetLogFormat(), logSource), size, nil)))
+}
+
+// Enabled returns true, all records are retained.
+func (h *RingHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle retains the record and passes it to the next handler if it is at or above the handler's level. ERROR and
+// FATAL records are passed to the next handler after the retained records that have not been written.
+func (h *RingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelError {
+		h.ring.add(ringEntry{ctx: ctx, handler: h.next, record: r.Clone()})
+		return h.ring.dump()
+	}
+
+	write := r.Level >= h.minLevel(r)
+	h.ring.add(ringEntry{ctx: ctx, handler: h.next, record: r.Clone(), written: write})
+	if !write {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// Dump passes the retained records that have not been written to the next handler.
+func (h *RingHandler) Dump() error {
+	return h.ring.dump()
+}
+
+// WithAttrs returns a new RingHandler sharing the ring buffer whose next handler has the given attributes.
+func (h *RingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &RingHandler{next: h.next.WithAttrs(attrs), level: h.level, ring: h.ring}
+}
+
+// WithGroup returns a new RingHandler sharing the ring buffer whose next handler has the given group.
+func (h *RingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &RingHandler{next: h.next.WithGroup(name), level: h.level, ring: h.ring}
+}
+
+// minLevel returns the level at which a record is written immediately.
+func (h *RingHandler) minLevel(r slog.Record) slog.Level {
+	if h.level != nil {
+		return h.level.Level()
+	}
+	return LevelFor(packageOfPC(r.PC))
+}
+
+// add adds an entry to the ring buffer, replacing the oldest entry if the buffer is full.
+func (b *ringBuffer) add(entry ringEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// dump writes the entries that have not been written, oldest first, and marks them as written.
+func (b *ringBuffer) dump() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	start, count := 0, b.next
+	if b.full {
+		start, count = b.next, len(b.entries)
+	}
+
+	var errs []error
+	for i := range count {
+		entry := &b.entries[(start+i)%len(b.entries)]
+		if entry.written {
+			continue
+		}
+		entry.written = true
+		if err := entry.handler.Handle(entry.ctx, entry.record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RecoverAndDump recovers from a panic and reports it using LogFatal, causing any RingHandler used by the logger to write
+// its retained records before the process exits. It should be deferred, e.g. at the start of main.
+func RecoverAndDump(ctx context.Context, log *slog.Logger) {
+	if r := recover(); r != nil {
+		LogFatal(ctx, log, "recovered from panic", "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+	}
+}
@@ -0,0 +1,345 @@
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	compressSuffix   = ".gz"
+	startSuffix      = ".start"
+	logFileMode      = 0o644
+	logDirMode       = 0o755
+)
+
+var errFileClosed = errors.New("rotating file is closed")
+
+// RotatingFileConfig holds the configuration of a RotatingFile.
+type RotatingFileConfig struct {
+	Filename   string        // The file to write to, backups are created in the same directory.
+	MaxSize    int64         // The size in bytes at which the file is rotated, zero disables size based rotation.
+	MaxAge     time.Duration // The time after which the file is rotated, zero disables age based rotation.
+	MaxBackups int           // The number of backups to retain, zero retains all backups.
+	Compress   bool          // Compress backups using gzip.
+}
+
+// RotatingFile is an io.Writer that writes to a file, rotating it when it reaches a maximum size or age.
+// It is safe for concurrent use and can be passed to NewLoggerTo and NewTextLoggerTo.
+type RotatingFile struct {
+	config   RotatingFileConfig
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+	cleanup  sync.WaitGroup
+	cleanMu  sync.Mutex
+}
+
+// NewRotatingFile returns a RotatingFile writing to the file specified in the configuration.
+func NewRotatingFile(config RotatingFileConfig) (*RotatingFile, error) {
+	r := &RotatingFile{config: config}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write writes data to the file, rotating it first if the write would exceed the maximum size or the file is too old.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, errFileClosed
+	}
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.needsRotation(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	if r.size == 0 {
+		r.openedAt = time.Now()
+		r.recordStart()
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Rotate closes the current file, renames it to a backup and opens a new file.
+func (r *RotatingFile) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return errFileClosed
+	}
+	return r.rotate()
+}
+
+// Reopen closes and reopens the file, this should be called after the file has been moved by an external tool such as logrotate.
+func (r *RotatingFile) Reopen() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return errFileClosed
+	}
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+// Close closes the file, waiting for any backup compression and removal to complete.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	err := r.closeFile()
+	r.mu.Unlock()
+
+	r.cleanup.Wait()
+	return err
+}
+
+// needsRotation reports whether the file should be rotated before writing the number of bytes specified, an empty
+// file is never rotated.
+func (r *RotatingFile) needsRotation(length int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.config.MaxSize > 0 && r.size+length > r.config.MaxSize {
+		return true
+	}
+	return r.config.MaxAge > 0 && time.Since(r.openedAt) > r.config.MaxAge
+}
+
+// open opens the file for appending, creating the directory and file if needed. The age of a file starts when it is
+// first written to, this time is kept in a hidden start file next to it so the age is not reset when a process
+// restarts. The modification time is used for an existing file without a start file.
+func (r *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.config.Filename), logDirMode); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(r.config.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFileMode)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to get log file info: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	r.openedAt = time.Time{}
+	if r.size > 0 {
+		start, ok := readStart(r.config.Filename)
+		r.openedAt = start
+		if !ok || start.After(info.ModTime()) {
+			r.openedAt = info.ModTime()
+			r.recordStart()
+		}
+	}
+	return nil
+}
+
+// recordStart writes the time the file was first written to, to the start file.
+func (r *RotatingFile) recordStart() {
+	if err := writeStart(r.config.Filename, r.openedAt); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to record log file start time: %s, %s\n", r.config.Filename, err)
+	}
+}
+
+// startFile returns the name of the hidden file holding the time a log file was first written to.
+func startFile(filename string) string {
+	return filepath.Join(filepath.Dir(filename), "."+filepath.Base(filename)+startSuffix)
+}
+
+// readStart returns the time held in the start file of a log file, reporting false if there is no valid start file.
+func readStart(filename string) (time.Time, bool) {
+	data, err := os.ReadFile(startFile(filename))
+	if err != nil {
+		return time.Time{}, false
+	}
+	start, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(data)))
+	return start, err == nil
+}
+
+// writeStart writes a time to the start file of a log file.
+func writeStart(filename string, start time.Time) error {
+	return os.WriteFile(startFile(filename), []byte(start.UTC().Format(time.RFC3339Nano)+"\n"), logFileMode)
+}
+
+// closeFile closes the current file, if open.
+func (r *RotatingFile) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// rotate renames the current file to a backup, opens a new file and starts the removal of old backups.
+func (r *RotatingFile) rotate() error {
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+
+	backup := r.backupName(time.Now())
+	for seq := 1; exists(backup) || exists(backup+compressSuffix); seq++ {
+		backup = r.backupName(time.Now(), seq)
+	}
+	if err := os.Rename(r.config.Filename, backup); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to rename log file: %w", err)
+	}
+
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	r.cleanup.Add(1)
+	go func() {
+		defer r.cleanup.Done()
+		r.cleanBackups(backup)
+	}()
+	return nil
+}
+
+// backupName returns the name of a backup file created at the time specified, with a sequence number if one is
+// specified to distinguish backups created within the same millisecond.
+func (r *RotatingFile) backupName(t time.Time, seq ...int) string {
+	ext := filepath.Ext(r.config.Filename)
+	prefix := strings.TrimSuffix(r.config.Filename, ext)
+	stamp := t.UTC().Format(backupTimeFormat)
+	if len(seq) > 0 {
+		stamp = fmt.Sprintf("%s-%d", stamp, seq[0])
+	}
+	return fmt.Sprintf("%s-%s%s", prefix, stamp, ext)
+}
+
+// exists reports whether a file exists.
+func exists(name string) bool {
+	_, err := os.Lstat(name)
+	return err == nil
+}
+
+// cleanBackups compresses the new backup if required and removes backups exceeding the maximum number retained.
+func (r *RotatingFile) cleanBackups(backup string) {
+	r.cleanMu.Lock()
+	defer r.cleanMu.Unlock()
+
+	if r.config.Compress {
+		if err := compressFile(backup); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to compress log file: %s, %s\n", backup, err)
+		}
+	}
+
+	if r.config.MaxBackups <= 0 {
+		return
+	}
+
+	backups, err := r.backups()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to list log file backups: %s\n", err)
+		return
+	}
+	for i := r.config.MaxBackups; i < len(backups); i++ {
+		if err := os.Remove(backups[i]); err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "failed to remove log file backup: %s, %s\n", backups[i], err)
+		}
+	}
+}
+
+// backups returns the backup files, newest first.
+func (r *RotatingFile) backups() ([]string, error) {
+	ext := filepath.Ext(r.config.Filename)
+	prefix := strings.TrimSuffix(r.config.Filename, ext) + "-"
+	matches, err := filepath.Glob(prefix + "*")
+	if err != nil {
+		return nil, err
+	}
+
+	type backup struct {
+		name string
+		time time.Time
+		seq  int
+	}
+	found := []backup{}
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(match, prefix), compressSuffix), ext)
+		seq := 0
+		if i := strings.LastIndex(stamp, "-"); i > len(backupTimeFormat)-1 {
+			if seq, err = strconv.Atoi(stamp[i+1:]); err != nil {
+				continue
+			}
+			stamp = stamp[:i]
+		}
+		if t, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			found = append(found, backup{name: match, time: t, seq: seq})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if !found[i].time.Equal(found[j].time) {
+			return found[i].time.After(found[j].time)
+		}
+		return found[i].seq > found[j].seq
+	})
+
+	backups := make([]string, 0, len(found))
+	for _, b := range found {
+		backups = append(backups, b.name)
+	}
+	return backups, nil
+}
+
+// compressFile compresses a file using gzip, removing the original.
+func compressFile(name string) error {
+	source, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	destination, err := os.OpenFile(name+compressSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, logFileMode)
+	if err != nil {
+		return err
+	}
+
+	writer := gzip.NewWriter(destination)
+	if _, err := io.Copy(writer, source); err != nil {
+		destination.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		destination.Close()
+		return err
+	}
+	if err := destination.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
@@ -0,0 +1,159 @@
+package logging_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paul-carlton/goutils/pkg/logging"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+
+	tests := []struct {
+		testNum  int
+		compress bool
+		suffix   string
+	}{
+		{1, false, ".log"},
+		{2, true, ".log.gz"},
+	}
+
+	for _, test := range tests {
+		r, err := logging.NewRotatingFile(logging.RotatingFileConfig{
+			Filename:   filename,
+			MaxSize:    100,
+			MaxBackups: 2,
+			Compress:   test.compress,
+		})
+		if err != nil {
+			t.Fatalf("\nTest: %d\nunexpected error: %s", test.testNum, err)
+		}
+
+		log := logging.NewLoggerTo(r)
+		for range 10 {
+			log.Info("a message long enough to fill the file quickly")
+		}
+		if err := r.Close(); err != nil {
+			t.Errorf("\nTest: %d\nunexpected error closing file: %s", test.testNum, err)
+		}
+
+		backups, _ := filepath.Glob(filepath.Join(dir, "app-*")) //nolint: errcheck
+		if len(backups) != 2 {
+			t.Errorf("\nTest: %d\nExpected: 2 backups\nGot.....: %v", test.testNum, backups)
+		}
+		for _, backup := range backups {
+			if !strings.HasSuffix(backup, test.suffix) {
+				t.Errorf("\nTest: %d\nExpected backup suffix: %s\nGot.....: %s", test.testNum, test.suffix, backup)
+			}
+			os.Remove(backup)
+		}
+
+		if info, err := os.Stat(filename); err != nil || info.Size() > 200 {
+			t.Errorf("\nTest: %d\nunexpected log file state, error: %v", test.testNum, err)
+		}
+	}
+}
+
+func TestRotatingFileBackToBack(t *testing.T) {
+	tests := []struct {
+		testNum  int
+		compress bool
+	}{
+		{1, false},
+		{2, true},
+	}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		r, err := logging.NewRotatingFile(logging.RotatingFileConfig{Filename: filepath.Join(dir, "app.log"), Compress: test.compress})
+		if err != nil {
+			t.Fatalf("\nTest: %d\nunexpected error: %s", test.testNum, err)
+		}
+		for _, line := range []string{"first\n", "second\n", "third\n"} {
+			if _, err := r.Write([]byte(line)); err != nil {
+				t.Fatalf("\nTest: %d\nunexpected error: %s", test.testNum, err)
+			}
+			if err := r.Rotate(); err != nil {
+				t.Fatalf("\nTest: %d\nunexpected error: %s", test.testNum, err)
+			}
+		}
+		if err := r.Close(); err != nil {
+			t.Errorf("\nTest: %d\nunexpected error closing file: %s", test.testNum, err)
+		}
+
+		backups, _ := filepath.Glob(filepath.Join(dir, "app-*")) //nolint: errcheck
+		content := ""
+		for _, backup := range backups {
+			data, _ := os.ReadFile(backup) //nolint: errcheck
+			if test.compress {
+				reader, err := gzip.NewReader(bytes.NewReader(data))
+				if err != nil {
+					t.Fatalf("\nTest: %d\nunexpected error: %s", test.testNum, err)
+				}
+				data, _ = io.ReadAll(reader) //nolint: errcheck
+			}
+			content += string(data)
+		}
+		for _, line := range []string{"first", "second", "third"} {
+			if len(backups) != 3 || !strings.Contains(content, line) {
+				t.Errorf("\nTest: %d\nExpected: 3 backups containing: %s\nGot.....: %v, %q", test.testNum, line, backups, content)
+			}
+		}
+	}
+}
+
+func TestRotatingFileMaxAge(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+
+	tests := []struct {
+		testNum  int
+		content  string
+		modified time.Time
+		start    time.Time
+		backups  int
+	}{
+		{1, "existing\n", old, time.Time{}, 1},
+		{2, "", old, time.Time{}, 0},
+		{3, "existing\n", now, old, 1},
+		{4, "existing\n", now, now.Add(-time.Minute), 0},
+		{5, "existing\n", now, time.Time{}, 0},
+	}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "app.log")
+		if err := os.WriteFile(filename, []byte(test.content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if !test.start.IsZero() {
+			if err := logging.SetRotatingFileStart(filename, test.start); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.Chtimes(filename, test.modified, test.modified); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := logging.NewRotatingFile(logging.RotatingFileConfig{Filename: filename, MaxAge: time.Hour})
+		if err != nil {
+			t.Fatalf("\nTest: %d\nunexpected error: %s", test.testNum, err)
+		}
+		_, _ = r.Write([]byte("new\n")) //nolint: errcheck
+		_, _ = r.Write([]byte("new\n")) //nolint: errcheck
+		_ = r.Close()                   //nolint: errcheck
+
+		backups, _ := filepath.Glob(filepath.Join(dir, "app-*")) //nolint: errcheck
+		if len(backups) != test.backups {
+			t.Errorf("\nTest: %d\nExpected: %d backups\nGot.....: %v", test.testNum, test.backups, backups)
+		}
+	}
+}
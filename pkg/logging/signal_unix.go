@@ -0,0 +1,59 @@
+//go:build unix
+
+package logging
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// WatchLevelSignal cycles the logging level through INFO, DEBUG and TRACE each time one of the signals is received,
+// SIGUSR1 is used if no signals are specified. It returns immediately, watching continues until the context is cancelled.
+func WatchLevelSignal(ctx context.Context, sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGUSR1}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ch:
+				CycleLogLevel()
+			}
+		}
+	}()
+}
+
+// ReopenOnSignal reopens the file each time one of the signals is received, SIGHUP is used if no signals are specified.
+// This allows an external tool such as logrotate to move the file. It returns immediately, watching continues until
+// the context is cancelled.
+func (r *RotatingFile) ReopenOnSignal(ctx context.Context, sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ch:
+				if err := r.Reopen(); err != nil {
+					internalLogger().Warn("failed to reopen log file", "file", r.config.Filename, "error", err)
+				}
+			}
+		}
+	}()
+}
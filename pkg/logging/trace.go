@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	// SpanIDKey is the attribute key used for the span identifier.
+	SpanIDKey = "span_id"
+	// ParentSpanIDKey is the attribute key used for the parent span identifier.
+	ParentSpanIDKey = "parent_span_id"
+	// ElapsedKey is the attribute key used for the time spent in a span.
+	ElapsedKey = "elapsed"
+	// ResultsKey is the attribute key used for the values returned by a function.
+	ResultsKey = "results"
+)
+
+// spanKey is the context key used to store the current span identifier.
+type spanKey struct{}
+
+// SpanEnd is returned by Trace and StartSpan, it should be called when the function exits to log the time spent in the
+// function together with any error and return values, e.g. using a deferred function literal and named return values.
+type SpanEnd func(err error, results ...any)
+
+// Trace logs entry to the calling function at TRACE level, with a new span identifier and the parent span identifier
+// held by the context. The arguments are key/value pairs and/or slog.Attr values describing the call.
+//
+//	func (g *apiClient) SetRepoVariable(repo, varName, varValue string) (err error) {
+//		end := logging.Trace(g.o.Ctx, "repo", repo, "variable", varName)
+//		defer func() { end(err) }()
+func Trace(ctx context.Context, args ...any) SpanEnd {
+	_, end := startSpan(ctx, 1, args)
+	return end
+}
+
+// StartSpan is the same as Trace but also returns a context holding the new span identifier, functions called using this
+// context log it as their parent span identifier.
+func StartSpan(ctx context.Context, args ...any) (context.Context, SpanEnd) {
+	return startSpan(ctx, 1, args)
+}
+
+// SpanFromContext returns the span identifier held by a context, or an empty string if there is none.
+func SpanFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(spanKey{}).(string) //nolint: errcheck
+	return id
+}
+
+// startSpan logs entry to the function 'skip' levels above the caller of startSpan and returns a context holding the
+// span identifier and a function that logs the exit. Both records report the source of the call to Trace or StartSpan,
+// so the exit is attributed to the traced function even when it is logged from a deferred function literal.
+func startSpan(ctx context.Context, skip int, args []any) (context.Context, SpanEnd) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !TraceLog.Enabled(ctx, LevelTrace) {
+		return ctx, func(error, ...any) {}
+	}
+
+	spanAttrs := []any{slog.String(SpanIDKey, newSpanID())}
+	if parent := SpanFromContext(ctx); len(parent) > 0 {
+		spanAttrs = append(spanAttrs, slog.String(ParentSpanIDKey, parent))
+	}
+
+	pc := callerPC(skip + 1)
+	logPC(ctx, TraceLog, pc, LevelTrace, "Entering function", append(spanAttrs, args...)...)
+
+	start := time.Now()
+	spanCtx := context.WithValue(ctx, spanKey{}, spanAttrs[0].(slog.Attr).Value.String()) //nolint: forcetypeassert
+	return spanCtx, func(err error, results ...any) {
+		exitAttrs := append(spanAttrs, slog.Duration(ElapsedKey, time.Since(start))) //nolint: gocritic
+		if err != nil {
+			exitAttrs = append(exitAttrs, slog.String("error", err.Error()))
+		}
+		if len(results) > 0 {
+			exitAttrs = append(exitAttrs, slog.Any(ResultsKey, results))
+		}
+		logPC(ctx, TraceLog, pc, LevelTrace, "Exiting function", exitAttrs...)
+	}
+}
+
+// newSpanID returns a new random span identifier.
+func newSpanID() string {
+	return fmt.Sprintf("%016x", rand.Uint64()) //nolint: gosec
+}
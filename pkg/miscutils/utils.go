@@ -19,6 +19,8 @@ import (
 	"github.com/paul-carlton/goutils/pkg/logging"
 )
 
+// NewObjParams holds the parameters common to object constructors, log attributes added to Ctx using
+// logging.ContextWith are included in messages logged by the object.
 type NewObjParams struct {
 	Ctx    context.Context
 	Log    *slog.Logger
@@ -46,7 +48,7 @@ func (u *Utils) StringToFile(f *os.File, text string) error {
 	defer logging.TraceExit()
 
 	if _, err := f.WriteString(text); err != nil {
-		return logging.ErrorReport(fmt.Sprintf("failed to write to %s file", f.Name()), err)
+		return logging.WrapError(err, "failed to write to file", "file", f.Name())
 	}
 	return nil
 }
@@ -160,31 +162,30 @@ func PromptUsageWarning(o *NewObjParams) {
 	yes := "y"
 	if input != yes {
 		LogErrorFatal(o, "exiting script")
-		os.Exit(1)
 	}
 }
 
+// LogWarning logs the text at WARN level, the console handler colours it yellow.
 func LogWarning(o *NewObjParams, text string) {
-	warnOutput := color.New(color.FgYellow).SprintFunc()
-	o.Log.Warn(warnOutput(text))
+	o.Log.WarnContext(o.Ctx, text)
 }
 
+// LogInfo logs the text at INFO level, the console handler colours the level green.
 func LogInfo(o *NewObjParams, text string) {
-	infoOutput := color.New(color.FgGreen).SprintFunc()
-	o.Log.Info(infoOutput(text))
+	o.Log.InfoContext(o.Ctx, text)
 }
 
+// LogInfoBlue logs the text at INFO level with the highlight attribute, the console handler colours it blue.
 func LogInfoBlue(o *NewObjParams, text string) {
-	infoOutput := color.New(color.Bold, color.FgBlue).SprintFunc()
-	o.Log.Info(infoOutput(text))
+	o.Log.InfoContext(o.Ctx, text, logging.Highlight())
 }
 
+// LogError logs the text at ERROR level, the console handler colours it red.
 func LogError(o *NewObjParams, text string) {
-	errorOutput := color.New(color.FgRed).SprintFunc()
-	o.Log.Error(errorOutput(text))
+	o.Log.ErrorContext(o.Ctx, text)
 }
 
+// LogErrorFatal logs the text at FATAL level and exits, see logging.LogFatal.
 func LogErrorFatal(o *NewObjParams, text string) {
-	errorOutput := color.New(color.FgRed).SprintFunc()
-	o.Log.Log(o.Ctx, logging.LevelFatal, errorOutput(text))
+	logging.LogFatalDepth(o.Ctx, o.Log, 1, text)
 }
@@ -18,10 +18,11 @@ type messageBody struct {
 
 type messages struct {
 	Messages
-	o           *miscutils.NewObjParams
-	dryRun      bool
-	postURL     url.URL
-	httpReqResp httpclient.ReqResp
+	o          *miscutils.NewObjParams
+	dryRun     bool
+	postURL    url.URL
+	webhook    string
+	httpClient *httpclient.Client
 }
 
 type Messages interface {
@@ -32,37 +33,51 @@ func NewMessages(objParams *miscutils.NewObjParams, httpClient *http.Client) Mes
 	logging.TraceCall()
 	defer logging.TraceExit()
 
+	creds := os.Getenv("SLACK_CHANNEL_CREDS")
 	s := messages{
-		o:      objParams,
-		dryRun: strings.EqualFold(os.Getenv("NO_SLACK"), "true"),
-		postURL: url.URL{Scheme: "https", Host: "hooks.slack.com",
-			Path: fmt.Sprintf("services/%s", os.Getenv("SLACK_CHANNEL_CREDS"))},
-	}
-	var err error
-	if s.httpReqResp, err = httpclient.NewReqResp(objParams, nil, httpClient, nil); err != nil {
-		s.o.Log.Error("failed to get httpReqResp", "error", err)
+		o:       objParams,
+		dryRun:  strings.EqualFold(os.Getenv("NO_SLACK"), "true"),
+		postURL: url.URL{Scheme: "https", Host: "hooks.slack.com", Path: fmt.Sprintf("services/%s", creds)},
+		webhook: webhookID(creds),
 	}
+	s.httpClient = httpclient.NewClient(objParams, nil, httpClient, nil)
 
 	return &s
 }
 
-func (s *messages) Post(message string) error {
+func (s *messages) Post(message string) (err error) {
 	logging.TraceCall()
 	defer logging.TraceExit()
+	defer func() {
+		logging.Audit(s.o.Ctx, logging.AuditEvent{
+			Actor:  s.webhook,
+			Action: "Post",
+			Target: "slack",
+			Params: map[string]any{"message": message},
+			DryRun: s.dryRun,
+		}, err)
+	}()
 
 	if s.dryRun {
 		fmt.Fprint(s.o.LogOut, message)
 		return nil
 	}
 
-	method := "POST"
 	body := messageBody{Text: message}
-	if logging.LogLevel <= logging.LevelTrace {
-		fmt.Fprintf(s.o.LogOut, "body...\n%s\n", miscutils.IndentJSON(body, 0, 2)) //nolint: mnd
-	}
-	if err := s.httpReqResp.HTTPreq(&method, &s.postURL, miscutils.IndentJSON(body, 0, 2), nil); err != nil {
+	logging.LogDump(s.o.Ctx, s.o.Log, logging.LevelTrace, "body", body)
+	if _, err = s.httpClient.Do(s.o.Ctx, httpclient.Request{Method: httpclient.Post, URL: &s.postURL, Body: body}); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// webhookID returns the workspace and webhook IDs from the webhook credentials, which are of the form
+// workspace/webhook/secret, for use as the audit actor. The secret is omitted.
+func webhookID(creds string) string {
+	parts := strings.Split(creds, "/")
+	if len(parts) < 2 { //nolint: mnd
+		return ""
+	}
+	return "slack-webhook:" + parts[0] + "/" + parts[1]
+}